@@ -0,0 +1,84 @@
+package heap
+
+// AdaptivePolicy describes when a heap switches its branching factor based on
+// the recent mix of operations. A wider heap makes Push cheaper, since sifting
+// up crosses fewer levels, while a narrower heap makes Pop cheaper, since each
+// level of sifting down compares fewer children.
+//
+// The ratio of pushes to pops is evaluated every Window operations. Above
+// IngestRatio the heap is rebuilt with IngestD; below DrainRatio it is rebuilt
+// with DrainD. Keeping IngestRatio above DrainRatio leaves a band in which the
+// current branching factor is kept, which prevents thrashing between the two.
+type AdaptivePolicy struct {
+	IngestD     int     // Branching factor for push-heavy phases
+	DrainD      int     // Branching factor for pop-heavy phases
+	IngestRatio float64 // Push:pop ratio above which IngestD is used
+	DrainRatio  float64 // Push:pop ratio below which DrainD is used
+	Window      int     // Number of operations between evaluations
+	MinSize     int     // Heaps smaller than this are never rebuilt
+}
+
+// DefaultAdaptivePolicy switches to d=8 while pushes outnumber pops two to one
+// and back to d=2 once pops outnumber pushes two to one.
+var DefaultAdaptivePolicy = AdaptivePolicy{
+	IngestD:     8,
+	DrainD:      2,
+	IngestRatio: 2,
+	DrainRatio:  0.5,
+	Window:      1024,
+	MinSize:     256,
+}
+
+// adaptiveState tracks the operations seen in the current evaluation window.
+type adaptiveState struct {
+	policy AdaptivePolicy
+	pushes int
+	pops   int
+}
+
+// WithAdaptiveBranching is an option that lets the heap change its branching
+// factor at runtime according to policy. Each switch rebuilds the heap in O(n).
+func WithAdaptiveBranching[T comparable](policy AdaptivePolicy) Option[T] {
+	return func(h *Heap[T]) {
+		h.adaptive = &adaptiveState{policy: policy}
+	}
+}
+
+// BranchingFactor returns the current branching factor of the heap.
+func (h *Heap[T]) BranchingFactor() int {
+	return h.d
+}
+
+// recordOp counts a push or pop and, at the end of each window, switches the
+// branching factor if the policy calls for it.
+func (h *Heap[T]) recordOp(push bool) {
+	a := h.adaptive
+	if push {
+		a.pushes++
+	} else {
+		a.pops++
+	}
+	if a.pushes+a.pops < a.policy.Window {
+		return
+	}
+
+	ratio := float64(a.pushes) / float64(max(a.pops, 1))
+	a.pushes, a.pops = 0, 0
+	if h.heapSize < a.policy.MinSize {
+		return
+	}
+	switch {
+	case ratio > a.policy.IngestRatio && h.d != a.policy.IngestD:
+		h.rebuild(a.policy.IngestD)
+	case ratio < a.policy.DrainRatio && h.d != a.policy.DrainD:
+		h.rebuild(a.policy.DrainD)
+	}
+}
+
+// rebuild re-heapifies the heap with branching factor d.
+func (h *Heap[T]) rebuild(d int) {
+	h.setBranching(d)
+	h.dirty = false
+	h.staged = 0
+	h.heapify()
+}
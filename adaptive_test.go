@@ -0,0 +1,44 @@
+package heap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveBranching(t *testing.T) {
+	policy := AdaptivePolicy{IngestD: 8, DrainD: 2, IngestRatio: 2, DrainRatio: 0.5, Window: 100, MinSize: 10}
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithAdaptiveBranching[int](policy))
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		heap.Push(rng.Intn(1000))
+	}
+	assert.Equal(t, 8, heap.BranchingFactor(), "push-heavy window did not switch to IngestD")
+
+	// A balanced window falls inside the hysteresis band and keeps d.
+	for i := 0; i < 50; i++ {
+		heap.Push(rng.Intn(1000))
+		heap.Pop()
+	}
+	assert.Equal(t, 8, heap.BranchingFactor(), "balanced window changed the branching factor")
+
+	prev := -1
+	for i := 0; i < 100; i++ {
+		v := heap.Pop()
+		assert.LessOrEqual(t, prev, v, "heap order broken after switching branching factor")
+		prev = v
+	}
+	assert.Equal(t, 2, heap.BranchingFactor(), "pop-heavy window did not switch to DrainD")
+}
+
+func TestAdaptiveBranchingMinSize(t *testing.T) {
+	policy := DefaultAdaptivePolicy
+	policy.Window = 10
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithAdaptiveBranching[int](policy))
+	for i := 0; i < 100; i++ {
+		heap.Push(i)
+	}
+	assert.Equal(t, 4, heap.BranchingFactor(), "heap below MinSize was rebuilt")
+}
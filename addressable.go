@@ -0,0 +1,12 @@
+package heap
+
+// AddressableHeap is the handle-based API shared by the pointer-based heaps in
+// the pairing and fibheap subpackages, where H is the handle type returned by
+// Push. Code written against it can switch between implementations.
+type AddressableHeap[T comparable, H any] interface {
+	Len() int
+	Peek() T
+	Pop() T
+	Push(value T) H
+	DecreaseKey(handle H, value T) bool
+}
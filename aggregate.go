@@ -0,0 +1,97 @@
+package heap
+
+// Aggregates holds running aggregates over the elements currently in a heap.
+// Min and Max are in heap order: Min is the element Pop would return next and
+// Max is the element it would return last.
+type Aggregates[T comparable] struct {
+	Count int     // Number of elements in the heap
+	Sum   float64 // Sum of the key of every element in the heap
+	Min   T       // Extremal element, the root of the heap
+	Max   T       // Element that orders after every other element
+}
+
+// aggregator maintains Aggregates incrementally as elements are pushed and popped.
+type aggregator[T comparable] struct {
+	key func(T) float64
+	sum float64
+	max T
+}
+
+// WithAggregates is an option that maintains the sum of key over all queued
+// elements, along with the count and the last element in heap order, so they
+// can be read in O(1) through Aggregates.
+func WithAggregates[T comparable](key func(T) float64) Option[T] {
+	return func(h *Heap[T]) {
+		h.agg = &aggregator[T]{key: key}
+	}
+}
+
+// Aggregates returns the running aggregates of the heap. Sum is zero unless the
+// heap was created with WithAggregates; Max is computed in O(n) in that case.
+// Lazily removed elements are purged first so they are not counted.
+func (h *Heap[T]) Aggregates() Aggregates[T] {
+	h.purge()
+	a := Aggregates[T]{Count: h.Len(), Min: h.Peek()}
+	if h.agg == nil {
+		a.Max = h.scanMax()
+		return a
+	}
+	a.Sum = h.agg.sum
+	a.Max = h.agg.max
+	return a
+}
+
+// added updates the aggregates after value has been pushed onto h.
+func (a *aggregator[T]) added(h *Heap[T], value T) {
+	a.sum += a.key(value)
+	if h.heapSize == 1 || h.lessFunc(a.max, value) {
+		a.max = value
+	}
+}
+
+// removed updates the aggregates after value has been taken out of h. The
+// last element only needs recomputing when value was the last element, which
+// for Pop means every remaining element is equal to it.
+func (a *aggregator[T]) removed(h *Heap[T], value T) {
+	a.sum -= a.key(value)
+	switch {
+	case h.heapSize == 0:
+		var zero T
+		a.max, a.sum = zero, 0
+	case !h.lessFunc(value, a.max):
+		a.max = h.scanMax()
+	}
+}
+
+// scanMax returns the element that orders after every other element. It only
+// needs to inspect the leaves, which are the last elements of the array.
+func (h *Heap[T]) scanMax() T {
+	h.ensureHeap()
+	var max T
+	if h.heapSize == 0 {
+		return max
+	}
+	firstLeaf := 0
+	if h.heapSize > 1 {
+		firstLeaf = h.parent(h.heapSize-1) + 1
+	}
+	max = h.data[firstLeaf]
+	for _, v := range h.data[firstLeaf+1 : h.heapSize] {
+		if h.lessFunc(max, v) {
+			max = v
+		}
+	}
+	return max
+}
+
+// recompute rebuilds the aggregates from the elements of h in O(n).
+func (a *aggregator[T]) recompute(h *Heap[T]) {
+	var zero T
+	a.sum, a.max = 0, zero
+	for i, v := range h.data[:h.heapSize] {
+		a.sum += a.key(v)
+		if i == 0 || h.lessFunc(a.max, v) {
+			a.max = v
+		}
+	}
+}
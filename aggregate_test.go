@@ -0,0 +1,49 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapAggregates(t *testing.T) {
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b },
+		WithAggregates[int](func(v int) float64 { return float64(v) }))
+
+	assert.Equal(t, Aggregates[int]{}, heap.Aggregates())
+
+	for _, v := range []int{5, 2, 9, 7, 1} {
+		heap.Push(v)
+	}
+	assert.Equal(t, Aggregates[int]{Count: 5, Sum: 24, Min: 1, Max: 9}, heap.Aggregates())
+
+	heap.Pop()
+	heap.Pop()
+	assert.Equal(t, Aggregates[int]{Count: 3, Sum: 21, Min: 5, Max: 9}, heap.Aggregates())
+
+	heap.Pop()
+	heap.Pop()
+	heap.Pop()
+	assert.Equal(t, Aggregates[int]{}, heap.Aggregates())
+}
+
+func TestHeapAggregatesWithoutOption(t *testing.T) {
+	heap := NewHeap[int](2, func(a, b int) bool { return a > b })
+	for _, v := range []int{5, 2, 9, 7, 1, 3} {
+		heap.Push(v)
+	}
+
+	assert.Equal(t, Aggregates[int]{Count: 6, Min: 9, Max: 1}, heap.Aggregates())
+}
+
+func TestHeapAggregatesLazyRemove(t *testing.T) {
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b },
+		WithAggregates[int](func(v int) float64 { return float64(v) }))
+	heap.PushAll(11, 5, 2, 9, 7, 1, 30, 20, 9)
+
+	assert.True(t, heap.LazyRemove(11))
+	assert.Equal(t, Aggregates[int]{Count: 8, Sum: 83, Min: 1, Max: 30}, heap.Aggregates())
+
+	assert.True(t, heap.LazyRemove(30))
+	assert.Equal(t, Aggregates[int]{Count: 7, Sum: 53, Min: 1, Max: 20}, heap.Aggregates())
+}
@@ -0,0 +1,50 @@
+package heap
+
+import "time"
+
+// PQOption is a type representing configurations for a PriorityQueue.
+type PQOption[V any, P any] func(*PriorityQueue[V, P])
+
+// agingPolicy derives the priority elements are ordered by from their stored
+// priority and how long they have been queued.
+type agingPolicy[P any] struct {
+	clock     Clock
+	effective func(P, time.Duration) P
+	interval  time.Duration
+	refreshed time.Time // When effective priorities were last recomputed
+}
+
+// WithAging is an option that orders the queue by an effective priority,
+// computed by effective from an element's stored priority and the time it
+// has spent queued, so that low-priority elements in a long-lived queue are
+// eventually served. Peek and Pop still report the stored priority.
+//
+// Effective priorities are recomputed, and the queue re-heapified in O(n),
+// at most once per interval, when the queue is next used; in between they
+// lag by up to interval. An interval of zero recomputes them on every
+// operation. If clock is nil the system clock is used.
+func WithAging[V any, P any](clock Clock, effective func(P, time.Duration) P, interval time.Duration) PQOption[V, P] {
+	return func(q *PriorityQueue[V, P]) {
+		if clock == nil {
+			clock = SystemClock{}
+		}
+		q.aging = &agingPolicy[P]{clock: clock, effective: effective, interval: interval, refreshed: clock.Now()}
+	}
+}
+
+// age recomputes every effective priority and restores the queue order if
+// the aging interval has elapsed, and returns the current time.
+func (q *PriorityQueue[V, P]) age() time.Time {
+	a := q.aging
+	now := a.clock.Now()
+	if now.Sub(a.refreshed) < a.interval {
+		return now
+	}
+	a.refreshed = now
+	for _, slot := range q.heap.data[:q.heap.heapSize] {
+		e := &q.entries[slot]
+		e.effective = a.effective(e.priority, now.Sub(e.enqueued))
+	}
+	q.heap.heapify()
+	return now
+}
@@ -0,0 +1,72 @@
+package heap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityQueueAging(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	// Lower is more urgent; every second queued is worth one priority level.
+	effective := func(p int, age time.Duration) int { return p - int(age/time.Second) }
+	q := NewPriorityQueue[string, int](2, func(a, b int) bool { return a < b },
+		WithAging[string, int](clock, effective, time.Second))
+
+	q.Push("background", 100)
+	clock.RunUntil(start.Add(95 * time.Second))
+	q.Push("urgent", 1)
+	v, p, ok := q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, "urgent", v)
+	assert.Equal(t, 1, p)
+
+	v, _ = q.Pop()
+	assert.Equal(t, "urgent", v)
+
+	clock.RunUntil(start.Add(99 * time.Second))
+	q.Push("fresh", 2)
+	v, p = q.Pop()
+	assert.Equal(t, "background", v, "aged past the fresh element")
+	assert.Equal(t, 100, p, "the stored priority is reported")
+	v, _ = q.Pop()
+	assert.Equal(t, "fresh", v)
+}
+
+func TestPriorityQueueAgingInterval(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	refreshes := 0
+	effective := func(p int, age time.Duration) int {
+		refreshes++
+		return p - int(age/time.Second)
+	}
+	q := NewPriorityQueue[int, int](4, func(a, b int) bool { return a < b },
+		WithAging[int, int](clock, effective, time.Minute))
+
+	for i := 0; i < 10; i++ {
+		q.Push(i, 10*i)
+	}
+	assert.Equal(t, 10, refreshes, "one computation per push")
+	q.Peek()
+	assert.Equal(t, 10, refreshes, "no refresh within the interval")
+
+	clock.RunUntil(start.Add(time.Minute))
+	q.Peek()
+	assert.Equal(t, 20, refreshes, "all priorities refreshed once")
+
+	assert.True(t, UpdatePriority(q, 0, 1000))
+	for i := 1; i < 10; i++ {
+		v, _ := q.Pop()
+		assert.Equal(t, i, v)
+	}
+	v, p := q.Pop()
+	assert.Equal(t, 0, v)
+	assert.Equal(t, 1000, p)
+}
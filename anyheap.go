@@ -0,0 +1,140 @@
+package heap
+
+import "iter"
+
+// AnyHeap is a d-ary heap whose elements only need to be ordered by the less
+// function, not compared for equality, so it can hold slices, maps, funcs or
+// structs containing them. Heap requires comparable elements because it
+// records each element's position in a map; AnyHeap keeps no such index and
+// so offers Push, Pop and Peek but not Contains, Get, Remove or Update.
+type AnyHeap[T any] struct {
+	data     []T             // Elements in heap order
+	d        int             // Branching factor
+	lessFunc func(T, T) bool // Function to determine order
+}
+
+// NewAnyHeap creates an empty d-ary heap ordered by lessFunc. It panics with
+// an error wrapping ErrInvalidBranchingFactor if d is less than 2.
+func NewAnyHeap[T any](d int, lessFunc func(T, T) bool) *AnyHeap[T] {
+	checkBranching(d)
+	return &AnyHeap[T]{d: d, lessFunc: lessFunc}
+}
+
+// Len returns the number of elements in the heap.
+func (h *AnyHeap[T]) Len() int {
+	return len(h.data)
+}
+
+// IsEmpty reports whether the heap holds no elements.
+func (h *AnyHeap[T]) IsEmpty() bool {
+	return len(h.data) == 0
+}
+
+// Push adds a new element to the heap in O(log_d n).
+func (h *AnyHeap[T]) Push(value T) {
+	h.data = append(h.data, value)
+	h.up(len(h.data) - 1)
+}
+
+// PushAll adds every item to the heap. A batch larger than the heap is merged
+// with a single O(n) heapify; a smaller one is sifted up item by item.
+func (h *AnyHeap[T]) PushAll(items ...T) {
+	n := len(h.data)
+	h.data = append(h.data, items...)
+	if len(items) > n {
+		heapifySlice(h.data, h.d, h.lessFunc)
+		return
+	}
+	for i := n; i < len(h.data); i++ {
+		h.up(i)
+	}
+}
+
+// up moves the element at index i towards the root until its parent does not
+// order after it.
+func (h *AnyHeap[T]) up(i int) {
+	for i > 0 {
+		p := (i - 1) / h.d
+		if !h.lessFunc(h.data[i], h.data[p]) {
+			return
+		}
+		h.data[i], h.data[p] = h.data[p], h.data[i]
+		i = p
+	}
+}
+
+// Peek returns the extremal element without removing it. If the heap is
+// empty, it returns the zero value of type T.
+func (h *AnyHeap[T]) Peek() T {
+	if len(h.data) == 0 {
+		var zero T
+		return zero
+	}
+	return h.data[0]
+}
+
+// Pop removes and returns the extremal element. If the heap is empty, it
+// returns the zero value of type T.
+func (h *AnyHeap[T]) Pop() T {
+	var zero T
+	n := len(h.data)
+	if n == 0 {
+		return zero
+	}
+	top := h.data[0]
+	popSlice(h.data, n, h.d, h.lessFunc)
+	h.data[n-1] = zero // Do not retain the element
+	h.data = h.data[:n-1]
+	return top
+}
+
+// TryPeek returns the extremal element without removing it, or ErrEmpty if
+// the heap is empty.
+func (h *AnyHeap[T]) TryPeek() (T, error) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return h.data[0], nil
+}
+
+// TryPop removes and returns the extremal element, or ErrEmpty if the heap is
+// empty.
+func (h *AnyHeap[T]) TryPop() (T, error) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return h.Pop(), nil
+}
+
+// Clear removes every element from the heap, keeping its backing array for
+// reuse.
+func (h *AnyHeap[T]) Clear() {
+	clear(h.data)
+	h.data = h.data[:0]
+}
+
+// All returns an iterator over the elements in array order, which is not
+// priority order. The heap must not be modified during iteration.
+func (h *AnyHeap[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range h.data {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Ordered returns an iterator over the elements in priority order, working on
+// a copy taken when iteration starts so the heap is not drained.
+func (h *AnyHeap[T]) Ordered() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range IncrementalSortFunc(h.data, h.d, h.lessFunc) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnyHeap(t *testing.T) {
+	t.Parallel()
+
+	// Slices are not comparable, so Heap cannot hold them.
+	byLen := func(a, b []int) bool { return len(a) < len(b) }
+	heap := NewAnyHeap(3, byLen)
+	assert.True(t, heap.IsEmpty())
+	assert.Nil(t, heap.Peek())
+	_, err := heap.TryPop()
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	heap.Push([]int{1, 2, 3})
+	heap.PushAll([]int{1}, nil, []int{1, 2, 3, 4})
+	heap.Push([]int{1, 2})
+	assert.Equal(t, 5, heap.Len())
+	top, err := heap.TryPeek()
+	assert.NoError(t, err)
+	assert.Empty(t, top)
+
+	var lens []int
+	for v := range heap.Ordered() {
+		lens = append(lens, len(v))
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, lens)
+	assert.Equal(t, 5, heap.Len(), "Ordered drained the heap")
+
+	lens = lens[:0]
+	for !heap.IsEmpty() {
+		lens = append(lens, len(heap.Pop()))
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, lens)
+	assert.Nil(t, heap.Pop())
+}
+
+func TestAnyHeapRandom(t *testing.T) {
+	t.Parallel()
+
+	type job struct {
+		priority int
+		run      func() // Makes job not comparable
+	}
+	rng := rand.New(rand.NewSource(4))
+	for _, d := range []int{2, 3, 5} {
+		heap := NewAnyHeap(d, func(a, b job) bool { return a.priority < b.priority })
+		var want []int
+		for i := 0; i < 200; i++ {
+			switch {
+			case i%50 == 0:
+				batch := make([]job, rng.Intn(100))
+				for k := range batch {
+					batch[k] = job{priority: rng.Intn(1000)}
+					want = append(want, batch[k].priority)
+				}
+				heap.PushAll(batch...)
+			case rng.Intn(3) == 0 && !heap.IsEmpty():
+				sort.Ints(want)
+				assert.Equal(t, want[0], heap.Pop().priority, "d=%d", d)
+				want = want[1:]
+			default:
+				p := rng.Intn(1000)
+				heap.Push(job{priority: p})
+				want = append(want, p)
+			}
+		}
+		assert.Equal(t, len(want), heap.Len())
+		assert.Equal(t, len(want), len(slices.Collect(heap.All())))
+		heap.Clear()
+		assert.True(t, heap.IsEmpty())
+	}
+
+	assert.Panics(t, func() { NewAnyHeap(1, func(a, b job) bool { return false }) })
+}
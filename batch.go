@@ -0,0 +1,103 @@
+package heap
+
+// PopN removes and returns up to n extremal elements in order. It returns
+// fewer than n elements if the heap runs out.
+func (h *Heap[T]) PopN(n int) []T {
+	h.ensureHeap()
+	n = max(min(n, h.heapSize), 0)
+	out := make([]T, 0, n)
+	for len(out) < n && !h.IsEmpty() {
+		out = append(out, h.Pop())
+	}
+	return out
+}
+
+// PeekN returns up to n extremal elements in order without modifying the
+// heap. Like KthSmallest it walks the tree with an auxiliary heap of at most
+// n·d positions, so it runs in O(n·d log n) regardless of the heap's size.
+// Expired elements are skipped.
+func (h *Heap[T]) PeekN(n int) []T {
+	h.ensureHeap()
+	n = max(min(n, h.Len()), 0)
+	out := make([]T, 0, n)
+	if n == 0 {
+		return out
+	}
+	h.walkOrder(func(i int) bool {
+		out = append(out, h.data[i])
+		return len(out) < n
+	})
+	return out
+}
+
+// PushAll adds every item to the heap. The items are appended first and then
+// merged in one pass: a batch that is small relative to the heap is sifted up
+// item by item, while a large batch is merged with a single O(n) heapify.
+func (h *Heap[T]) PushAll(items ...T) {
+	if h.maxSize > 0 {
+		for _, v := range items {
+			h.Push(v)
+		}
+		return
+	}
+	h.load(items)
+	h.mergeLoaded(len(items))
+}
+
+// mergeLoaded restores the heap property over the last n elements, which were
+// appended by load, unless the heap defers that to its next read.
+func (h *Heap[T]) mergeLoaded(n int) {
+	if n == 0 {
+		return
+	}
+	if h.dirty || h.lazy {
+		h.dirty = true
+		return
+	}
+	h.staged += n
+	h.mergeStaged()
+}
+
+// RemoveIf removes every element for which pred returns true and returns the
+// number removed. It makes one pass over the heap followed by a single O(n)
+// heapify, so purging many elements costs O(n) rather than O(k log n).
+func (h *Heap[T]) RemoveIf(pred func(T) bool) int {
+	return h.removeWhere(func(i int) bool { return pred(h.data[i]) })
+}
+
+// removeWhere removes every element whose index satisfies pred, which is
+// called once per index in ascending order before any element moves.
+func (h *Heap[T]) removeWhere(pred func(int) bool) int {
+	h.ensureHeap()
+	kept := 0
+	for i := 0; i < h.heapSize; i++ {
+		if !pred(i) {
+			h.swap(kept, i)
+			kept++
+		}
+	}
+	removed := h.heapSize - kept
+	if removed == 0 {
+		return 0
+	}
+	for i := kept; i < h.heapSize; i++ {
+		v := h.data[i]
+		h.removeIndex(v, i)
+		if h.items != nil {
+			h.releaseSlot(i)
+		}
+		if h.oplog != nil {
+			h.log(Op[T]{Kind: OpRemove, Value: v})
+		}
+	}
+	h.vacate(kept, h.heapSize)
+	h.heapSize = kept
+	if h.occupancy != nil {
+		h.occupancy.sample(h.heapSize)
+	}
+	h.heapify()
+	if h.agg != nil {
+		h.agg.recompute(h)
+	}
+	return removed
+}
@@ -0,0 +1,121 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapPopNPeekN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{name: "none", n: 0, want: []int{}},
+		{name: "negative", n: -1, want: []int{}},
+		{name: "some", n: 3, want: []int{1, 2, 2}},
+		{name: "more than held", n: 10, want: []int{1, 2, 2, 4, 7, 9}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			heap := NewHeap[int](3, func(a, b int) bool { return a < b })
+			for _, v := range []int{7, 2, 9, 1, 4, 2} {
+				heap.Push(v)
+			}
+
+			assert.Equal(t, tt.want, heap.PeekN(tt.n))
+			assert.Equal(t, 6, heap.Len(), "PeekN modified the heap")
+			assert.Equal(t, tt.want, heap.PopN(tt.n))
+			assert.Equal(t, 6-len(tt.want), heap.Len())
+		})
+	}
+}
+
+func TestHeapPushAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		existing int
+		batch    int
+		options  []Option[int]
+	}{
+		{name: "small batch", existing: 1000, batch: 10},
+		{name: "large batch", existing: 10, batch: 1000},
+		{name: "empty batch", existing: 10, batch: 0},
+		{name: "lazy", existing: 10, batch: 100, options: []Option[int]{WithLazyHeapify[int]()}},
+		{name: "staged", existing: 100, batch: 5, options: []Option[int]{WithStagingBuffer[int](8)}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewSource(int64(tt.batch)))
+			heap := NewHeap[int](4, func(a, b int) bool { return a < b }, tt.options...)
+			var want []int
+			for i := 0; i < tt.existing; i++ {
+				v := rng.Intn(500)
+				heap.Push(v)
+				want = append(want, v)
+			}
+			batch := make([]int, tt.batch)
+			for i := range batch {
+				batch[i] = rng.Intn(500)
+			}
+			want = append(want, batch...)
+
+			heap.PushAll(batch...)
+			assert.Equal(t, len(want), heap.Len())
+
+			sort.Ints(want)
+			for _, v := range want {
+				assert.Equal(t, v, heap.Pop())
+			}
+			assert.Empty(t, heap.index)
+		})
+	}
+}
+
+func TestHeapRemoveIf(t *testing.T) {
+	t.Parallel()
+
+	type task struct {
+		tenant string
+		seq    int
+	}
+	heap := NewHeap[task](4, func(a, b task) bool { return a.seq < b.seq },
+		WithAggregates[task](func(tk task) float64 { return float64(tk.seq) }))
+	for seq := 0; seq < 40; seq++ {
+		heap.Push(task{tenant: []string{"a", "b", "c"}[seq%3], seq: seq})
+	}
+	handle := heap.PushHandle(task{tenant: "b", seq: 40})
+
+	assert.Equal(t, 14, heap.RemoveIf(func(tk task) bool { return tk.tenant == "b" }))
+	assert.Zero(t, heap.RemoveIf(func(tk task) bool { return tk.tenant == "b" }))
+	_, ok := handle.Value()
+	assert.False(t, ok, "handle of a removed element is still valid")
+	assert.False(t, heap.Contains(task{tenant: "b", seq: 1}))
+	assert.True(t, heap.Contains(task{tenant: "c", seq: 2}))
+	assert.Equal(t, 27, heap.Len())
+	assert.Equal(t, 39, heap.Aggregates().Max.seq)
+
+	prev := -1
+	for !heap.IsEmpty() {
+		tk := heap.Pop()
+		assert.NotEqual(t, "b", tk.tenant)
+		assert.Less(t, prev, tk.seq)
+		prev = tk.seq
+	}
+	assert.Empty(t, heap.index)
+}
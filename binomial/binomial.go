@@ -0,0 +1,153 @@
+// Package binomial provides a generic binomial heap. It takes the same less
+// function as the d-ary heap package and mirrors its Push, Pop and Peek API;
+// its selling point is Meld, which unions two heaps in O(log n) rather than
+// the O(n) of rebuilding an array-backed heap, for example when per-worker
+// queues are merged after every batch.
+package binomial
+
+// node is the root of a binomial tree of order degree.
+type node[T any] struct {
+	value   T
+	degree  int
+	child   *node[T] // Child of the highest order
+	sibling *node[T] // Next root, or next lower-order child
+}
+
+// Heap is a binomial heap ordered by a less function.
+type Heap[T any] struct {
+	head     *node[T] // Roots in increasing order of degree
+	size     int
+	lessFunc func(T, T) bool
+}
+
+// NewHeap creates an empty binomial heap ordered by lessFunc.
+func NewHeap[T any](lessFunc func(T, T) bool) *Heap[T] {
+	return &Heap[T]{lessFunc: lessFunc}
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return h.size
+}
+
+// Peek returns the extremal element without removing it in O(log n). If the
+// heap is empty, it returns the zero value of type T.
+func (h *Heap[T]) Peek() T {
+	if best, _ := h.best(); best != nil {
+		return best.value
+	}
+	var zero T
+	return zero
+}
+
+// Push adds a new element to the heap in O(1) amortized time.
+func (h *Heap[T]) Push(value T) {
+	h.head = h.union(h.head, &node[T]{value: value})
+	h.size++
+}
+
+// Pop removes and returns the extremal element in O(log n). If the heap is
+// empty, it returns the zero value of type T.
+func (h *Heap[T]) Pop() T {
+	best, prev := h.best()
+	if best == nil {
+		var zero T
+		return zero
+	}
+	if prev == nil {
+		h.head = best.sibling
+	} else {
+		prev.sibling = best.sibling
+	}
+
+	// The children form a binomial heap in decreasing order of degree.
+	var children *node[T]
+	for c := best.child; c != nil; {
+		next := c.sibling
+		c.sibling = children
+		children = c
+		c = next
+	}
+	h.head = h.union(h.head, children)
+	h.size--
+	return best.value
+}
+
+// Meld moves every element of other into h in O(log n) and leaves other
+// empty. Both heaps must order elements the same way.
+func (h *Heap[T]) Meld(other *Heap[T]) {
+	if h == other {
+		return
+	}
+	h.head = h.union(h.head, other.head)
+	h.size += other.size
+	other.head, other.size = nil, 0
+}
+
+// best returns the root holding the extremal element and the root before it.
+func (h *Heap[T]) best() (best, prev *node[T]) {
+	var p *node[T]
+	for n := h.head; n != nil; p, n = n, n.sibling {
+		if best == nil || h.lessFunc(n.value, best.value) {
+			best, prev = n, p
+		}
+	}
+	return best, prev
+}
+
+// union merges two root lists, each in increasing order of degree, and links
+// trees of equal order like binary addition with carries.
+func (h *Heap[T]) union(a, b *node[T]) *node[T] {
+	head := mergeRoots(a, b)
+	if head == nil {
+		return nil
+	}
+	var prev *node[T]
+	n, next := head, head.sibling
+	for next != nil {
+		if n.degree != next.degree || (next.sibling != nil && next.sibling.degree == n.degree) {
+			prev, n = n, next
+		} else if !h.lessFunc(next.value, n.value) {
+			n.sibling = next.sibling
+			link(next, n)
+		} else {
+			if prev == nil {
+				head = next
+			} else {
+				prev.sibling = next
+			}
+			link(n, next)
+			n = next
+		}
+		next = n.sibling
+	}
+	return head
+}
+
+// mergeRoots merges two root lists by degree without linking.
+func mergeRoots[T any](a, b *node[T]) *node[T] {
+	var head node[T]
+	tail := &head
+	for a != nil && b != nil {
+		if a.degree <= b.degree {
+			tail.sibling, a = a, a.sibling
+		} else {
+			tail.sibling, b = b, b.sibling
+		}
+		tail = tail.sibling
+	}
+	if a != nil {
+		tail.sibling = a
+	} else {
+		tail.sibling = b
+	}
+	return head.sibling
+}
+
+// link makes the tree rooted at child a subtree of parent; both have the same
+// order.
+func link[T any](child, parent *node[T]) {
+	child.sibling = parent.child
+	parent.child = child
+	parent.degree++
+}
@@ -0,0 +1,59 @@
+package binomial
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinomialHeap(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(1))
+	heap := NewHeap[int](func(a, b int) bool { return a > b })
+	assert.Zero(t, heap.Pop())
+	assert.Zero(t, heap.Peek())
+
+	values := make([]int, 777)
+	for i := range values {
+		values[i] = rng.Intn(500)
+		heap.Push(values[i])
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	assert.Equal(t, values[0], heap.Peek())
+	for _, want := range values {
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.Zero(t, heap.Len())
+}
+
+func TestBinomialHeapMeld(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	workers := make([]*Heap[int], 5)
+	var want []int
+	for w := range workers {
+		workers[w] = NewHeap(less)
+		for i := 0; i < 10*w+3; i++ {
+			v := w*1000 - i*7
+			workers[w].Push(v)
+			want = append(want, v)
+		}
+	}
+
+	merged := NewHeap(less)
+	for _, w := range workers {
+		merged.Meld(w)
+		assert.Zero(t, w.Len())
+	}
+	merged.Meld(merged)
+
+	sort.Ints(want)
+	assert.Equal(t, len(want), merged.Len())
+	for _, v := range want {
+		assert.Equal(t, v, merged.Pop())
+	}
+}
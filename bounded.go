@@ -0,0 +1,81 @@
+package heap
+
+// WithMaxSize is an option that caps the heap at n elements. Once the heap is
+// full, Push evicts the extremal element to make room for the new one, or
+// drops the new one if it would be evicted first itself. A min-heap capped at
+// k therefore keeps the k largest elements pushed, which is the building
+// block for streaming top-k. Push, PushAll, PushEvict and PushHandle respect
+// the limit; TryPush rejects pushes instead. Operations that add elements in
+// bulk, such as Meld, CopyFrom, Swap, Thaw, UnmarshalJSON and ReadFrom, evict
+// extremal elements afterwards until the heap is back within the limit.
+func WithMaxSize[T comparable](n int) Option[T] {
+	return func(h *Heap[T]) {
+		h.maxSize = n
+	}
+}
+
+// full reports whether the live elements of the heap have reached its maximum
+// size. Lazily removed elements do not count towards the limit.
+func (h *Heap[T]) full() bool {
+	return h.maxSize > 0 && h.Len() >= h.maxSize
+}
+
+// PushEvict pushes value and returns the element evicted to stay within the
+// maximum size, which is either the previous extremal element or value
+// itself. It returns the zero value of type T and false if nothing was
+// evicted.
+func (h *Heap[T]) PushEvict(value T) (T, bool) {
+	return h.pushEvict(value)
+}
+
+// pushEvict implements PushEvict, reporting the evicted element to the
+// eviction hook.
+func (h *Heap[T]) pushEvict(value T) (T, bool) {
+	if h.full() {
+		h.ensureTop() // Expired elements may be holding the room
+	}
+	if !h.full() {
+		h.push(value, nil)
+		var zero T
+		return zero, false
+	}
+	evicted := value
+	if h.lessFunc(h.data[0], value) {
+		evicted = h.replaceRoot(value)
+		if h.hooks != nil {
+			h.hooks.pushed(value)
+		}
+	}
+	if h.hooks != nil {
+		h.hooks.evicted(evicted)
+	}
+	return evicted, true
+}
+
+// trim evicts extremal elements until the heap is within its maximum size,
+// for operations that add elements in bulk. The heap must have a comparator.
+func (h *Heap[T]) trim() {
+	if h.maxSize <= 0 {
+		return
+	}
+	for h.Len() > h.maxSize {
+		h.ensureTop()
+		evicted := h.removeAt(0)
+		if h.hooks != nil {
+			h.hooks.evicted(evicted)
+		}
+	}
+}
+
+// TryPush pushes value, or returns ErrFull without modifying the heap if the
+// heap is at its maximum size.
+func (h *Heap[T]) TryPush(value T) error {
+	if h.full() {
+		h.ensureTop() // Expired elements may be holding the room
+	}
+	if h.full() {
+		return ErrFull
+	}
+	h.Push(value)
+	return nil
+}
@@ -0,0 +1,157 @@
+package heap
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMaxSize(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b }, WithMaxSize[int](3))
+	heap.PushAll(5, 1, 4)
+	assert.Equal(t, 3, heap.Len())
+
+	evicted, ok := heap.PushEvict(3)
+	assert.True(t, ok)
+	assert.Equal(t, 1, evicted, "extremal element evicted")
+
+	evicted, ok = heap.PushEvict(2)
+	assert.True(t, ok)
+	assert.Equal(t, 2, evicted, "value that would be evicted first is rejected")
+
+	heap.Push(9)
+	heap.PushAll(0, 8)
+	assert.ErrorIs(t, heap.TryPush(10), ErrFull)
+	assert.Equal(t, []int{5, 8, 9}, heap.PopN(3))
+
+	assert.NoError(t, heap.TryPush(10))
+	_, ok = heap.PushEvict(11)
+	assert.False(t, ok)
+	assert.Equal(t, 2, heap.Len())
+}
+
+func TestWithMaxSizeBulkPaths(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	bounded := func() *Heap[int] { return NewHeap(2, less, WithMaxSize[int](3)) }
+	filled := func(values ...int) *Heap[int] {
+		heap := NewHeap(2, less)
+		heap.PushAll(values...)
+		return heap
+	}
+
+	tests := []struct {
+		name string
+		fill func(t *testing.T) *Heap[int]
+	}{
+		{"Meld", func(t *testing.T) *Heap[int] {
+			heap := bounded()
+			heap.PushAll(1, 5, 3)
+			heap.Meld(filled(6, 2, 4))
+			return heap
+		}},
+		{"CopyFrom", func(t *testing.T) *Heap[int] {
+			heap := bounded()
+			heap.CopyFrom(filled(1, 2, 3, 4, 5, 6))
+			return heap
+		}},
+		{"Swap", func(t *testing.T) *Heap[int] {
+			heap := bounded()
+			other := filled(1, 2, 3, 4, 5, 6)
+			heap.Swap(other)
+			assert.Equal(t, 0, other.Len())
+			return heap
+		}},
+		{"Thaw", func(t *testing.T) *Heap[int] {
+			return filled(1, 2, 3, 4, 5, 6).Freeze().Thaw(2, WithMaxSize[int](3))
+		}},
+		{"UnmarshalJSON", func(t *testing.T) *Heap[int] {
+			data, err := filled(1, 2, 3, 4, 5, 6).MarshalJSON()
+			assert.NoError(t, err)
+			heap := bounded()
+			assert.NoError(t, heap.UnmarshalJSON(data))
+			return heap
+		}},
+		{"ReadFrom", func(t *testing.T) *Heap[int] {
+			var buf bytes.Buffer
+			_, err := filled(1, 2, 3, 4, 5, 6).WriteTo(&buf)
+			assert.NoError(t, err)
+			heap := bounded()
+			_, err = heap.ReadFrom(&buf)
+			assert.NoError(t, err)
+			return heap
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			heap := tt.fill(t)
+			assert.Equal(t, 3, heap.Len())
+			assert.Equal(t, []int{4, 5, 6}, heap.PopN(3))
+		})
+	}
+}
+
+func TestWithMaxSizePushHandle(t *testing.T) {
+	t.Parallel()
+
+	var evicted []int
+	heap := NewHeap(2, func(a, b int) bool { return a < b },
+		WithMaxSize[int](2), WithOnEvict(func(v int) { evicted = append(evicted, v) }))
+	heap.PushHandle(3)
+	heap.PushHandle(5)
+
+	it := heap.PushHandle(4)
+	assert.Equal(t, 2, heap.Len())
+	value, ok := it.Value()
+	assert.True(t, ok)
+	assert.Equal(t, 4, value)
+	assert.Equal(t, []int{3}, evicted)
+
+	it = heap.PushHandle(1)
+	assert.Equal(t, 2, heap.Len())
+	_, ok = it.Value()
+	assert.False(t, ok, "evicted value has a dead handle")
+	assert.False(t, heap.RemoveHandle(it))
+	assert.Equal(t, []int{3, 1}, evicted)
+	assert.Equal(t, []int{4, 5}, heap.PopN(2))
+}
+
+func TestWithMaxSizeLazyRemove(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap(2, func(a, b int) bool { return a < b }, WithMaxSize[int](3))
+	heap.PushAll(1, 2, 3)
+	assert.True(t, heap.LazyRemove(2))
+
+	assert.NoError(t, heap.TryPush(4), "deleted element does not count towards the limit")
+	assert.Equal(t, 3, heap.Len())
+	assert.ErrorIs(t, heap.TryPush(5), ErrFull)
+
+	evicted, ok := heap.PushEvict(5)
+	assert.True(t, ok)
+	assert.Equal(t, 1, evicted)
+	assert.Equal(t, []int{3, 4, 5}, heap.PopN(3))
+}
+
+func TestWithMaxSizeExpired(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	heap := NewMin[int](2, WithTTLClock[int](clock), WithMaxSize[int](2))
+	heap.PushWithTTL(1, time.Second)
+	heap.Push(2)
+	assert.ErrorIs(t, heap.TryPush(3), ErrFull)
+
+	clock.RunUntil(start.Add(time.Second))
+	assert.NoError(t, heap.TryPush(3), "expired root does not count towards the limit")
+	assert.Equal(t, []int{2, 3}, heap.PopN(2))
+}
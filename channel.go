@@ -0,0 +1,91 @@
+package heap
+
+// Out returns a channel that delivers the heap's elements in priority order,
+// so consumers can select on the next element together with timeouts or
+// shutdown signals. The first call starts a goroutine that feeds the channel;
+// later calls return the same channel.
+//
+// The feeder holds at most one element outside the heap while it waits for a
+// receiver. If a push brings in an element that orders before it, the held
+// element goes back into the heap and the new one is offered instead. Len
+// does not count the held element. CloseOut stops the feeder.
+func (c *ConcurrentHeap[T]) Out() <-chan T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.out == nil {
+		c.out = make(chan T)
+		c.changed = make(chan struct{}, 1)
+		c.stop = make(chan struct{})
+		go c.feed(c.stop)
+	}
+	return c.out
+}
+
+// CloseOut stops feeding the channel returned by Out, returns any held
+// element to the heap and closes the channel. It does nothing if Out was
+// never called or the channel is already closed.
+func (c *ConcurrentHeap[T]) CloseOut() {
+	c.mu.Lock()
+	stop := c.stop
+	c.stop = nil
+	c.mu.Unlock()
+	if stop != nil {
+		close(stop)
+	}
+}
+
+// notify wakes the feeder after a push. It must be called with c.mu held.
+func (c *ConcurrentHeap[T]) notify() {
+	if c.changed == nil {
+		return
+	}
+	select {
+	case c.changed <- struct{}{}:
+	default: // A wake-up is already pending
+	}
+}
+
+// feed moves elements from the heap to the out channel until stop is closed.
+func (c *ConcurrentHeap[T]) feed(stop <-chan struct{}) {
+	var held T
+	holding := false
+	for {
+		if !holding {
+			c.mu.Lock()
+			if !c.heap.IsEmpty() {
+				held, holding = c.heap.Pop(), true
+				c.publish()
+			}
+			c.mu.Unlock()
+		}
+		if !holding {
+			select {
+			case <-c.changed:
+				continue
+			case <-stop:
+				close(c.out)
+				return
+			}
+		}
+
+		select {
+		case c.out <- held:
+			holding = false
+		case <-c.changed:
+			c.mu.Lock()
+			if !c.heap.IsEmpty() && c.heap.lessFunc(c.heap.Peek(), held) {
+				c.heap.Push(held)
+				c.publish()
+				holding = false
+			}
+			c.mu.Unlock()
+		case <-stop:
+			c.mu.Lock()
+			c.heap.Push(held)
+			c.publish()
+			c.mu.Unlock()
+			close(c.out)
+			return
+		}
+	}
+}
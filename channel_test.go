@@ -0,0 +1,82 @@
+package heap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentHeapOut(t *testing.T) {
+	t.Parallel()
+
+	heap := NewConcurrentHeap[int](2, func(a, b int) bool { return a < b })
+	for _, v := range []int{5, 3, 8} {
+		heap.Push(v)
+	}
+	out := heap.Out()
+	assert.Equal(t, out, heap.Out(), "Out returned a different channel")
+
+	assert.Equal(t, 3, <-out)
+
+	// The feeder now holds 5. A more urgent push displaces it.
+	assert.Eventually(t, func() bool { return heap.Len() == 1 }, time.Second, time.Millisecond)
+	heap.Push(1)
+	assert.Eventually(t, func() bool {
+		v, ok := heap.PeekFast()
+		return ok && v == 5
+	}, time.Second, time.Millisecond, "held element not returned to the heap")
+	assert.Equal(t, 1, <-out)
+	assert.Equal(t, 5, <-out)
+
+	select {
+	case v := <-out:
+		assert.Equal(t, 8, v)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the last element")
+	}
+
+	// An empty heap blocks until something is pushed.
+	select {
+	case v := <-out:
+		t.Fatalf("received %d from an empty heap", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+	heap.Push(4)
+	assert.Equal(t, 4, <-out)
+}
+
+func TestConcurrentHeapCloseOut(t *testing.T) {
+	t.Parallel()
+
+	heap := NewConcurrentHeap[int](2, func(a, b int) bool { return a < b })
+	heap.CloseOut() // No feeder yet
+	heap.Push(2)
+	heap.Push(1)
+	out := heap.Out()
+	assert.Eventually(t, func() bool { return heap.Len() == 1 }, time.Second, time.Millisecond)
+
+	heap.CloseOut()
+	_, open := <-out
+	assert.False(t, open)
+	assert.Equal(t, 2, heap.Len(), "held element was lost")
+	v, _ := heap.Pop()
+	assert.Equal(t, 1, v)
+	heap.CloseOut()
+}
+
+func TestConcurrentHeapCloseOutImmediately(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 100; i++ {
+		heap := NewConcurrentHeap[int](2, func(a, b int) bool { return a < b })
+		out := heap.Out()
+		heap.CloseOut() // Before the feeder has started running
+		select {
+		case _, open := <-out:
+			assert.False(t, open)
+		case <-time.After(time.Second):
+			t.Fatal("channel not closed")
+		}
+	}
+}
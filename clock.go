@@ -0,0 +1,163 @@
+package heap
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a source of the current time. Scheduling code written against a
+// Clock works unchanged with either a virtual clock in simulations or the wall
+// clock in production.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock that reads the system time.
+type SystemClock struct{}
+
+// Now returns the current system time.
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// EventClock is a Clock tied to a heap of scheduled events. Events are callbacks
+// registered for a point in time and fire in time order as the clock advances.
+//
+// A virtual EventClock jumps straight to the next event when advanced, which
+// makes it suitable for discrete-event simulation. A wall EventClock sleeps
+// until the next event is due, so the same scheduling code runs in production.
+type EventClock struct {
+	mu      sync.Mutex
+	events  *Heap[int64]        // Event times in nanoseconds since the Unix epoch
+	pending map[int64][]func()  // Callbacks keyed by event time, FIFO within a time
+	now     time.Time           // Current virtual time (unused by wall clocks)
+	virtual bool                // Whether the clock is virtual
+	sleep   func(time.Duration) // Blocks wall clocks until an event is due
+}
+
+// NewVirtualClock creates a virtual EventClock whose time starts at start and
+// only moves when the clock is advanced.
+func NewVirtualClock(start time.Time) *EventClock {
+	return newEventClock(start, true)
+}
+
+// NewWallClock creates an EventClock backed by the system clock. Advancing it
+// blocks until the next event is due.
+func NewWallClock() *EventClock {
+	return newEventClock(time.Time{}, false)
+}
+
+func newEventClock(start time.Time, virtual bool) *EventClock {
+	return &EventClock{
+		events:  NewHeap[int64](4, func(a, b int64) bool { return a < b }),
+		pending: make(map[int64][]func()),
+		now:     start,
+		virtual: virtual,
+		sleep:   time.Sleep,
+	}
+}
+
+// Now returns the current time of the clock.
+func (c *EventClock) Now() time.Time {
+	if !c.virtual {
+		return time.Now()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Schedule registers fn to run when the clock reaches at. Events scheduled for
+// the same instant run in the order they were scheduled.
+func (c *EventClock) Schedule(at time.Time, fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := at.UnixNano()
+	if _, exists := c.pending[key]; !exists {
+		c.events.Push(key)
+	}
+	c.pending[key] = append(c.pending[key], fn)
+}
+
+// Len returns the number of scheduled events that have not yet fired.
+func (c *EventClock) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, fns := range c.pending {
+		n += len(fns)
+	}
+	return n
+}
+
+// NextAt returns the time of the earliest scheduled event.
+// If no events are scheduled, it returns the zero time and false.
+func (c *EventClock) NextAt() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.events.heapSize == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, c.events.Peek()), true
+}
+
+// AdvanceToNext moves the clock to the earliest scheduled event and runs every
+// callback registered for that instant. A virtual clock jumps forward; a wall
+// clock sleeps until the event is due. It returns false if nothing is scheduled.
+func (c *EventClock) AdvanceToNext() bool {
+	next, ok := c.NextAt()
+	if !ok {
+		return false
+	}
+	c.advanceTo(next)
+	c.fire()
+	return true
+}
+
+// RunUntil runs every event scheduled at or before t in time order, including
+// events scheduled by callbacks while running, and then moves the clock to t.
+// It returns the number of callbacks that ran.
+func (c *EventClock) RunUntil(t time.Time) int {
+	ran := 0
+	for {
+		next, ok := c.NextAt()
+		if !ok || next.After(t) {
+			break
+		}
+		c.advanceTo(next)
+		ran += c.fire()
+	}
+	c.advanceTo(t)
+	return ran
+}
+
+// advanceTo moves the clock forward to t. Virtual clocks never move backwards.
+func (c *EventClock) advanceTo(t time.Time) {
+	if !c.virtual {
+		if wait := time.Until(t); wait > 0 {
+			c.sleep(wait)
+		}
+		return
+	}
+	c.mu.Lock()
+	if t.After(c.now) {
+		c.now = t
+	}
+	c.mu.Unlock()
+}
+
+// fire pops the earliest event and runs its callbacks without holding the
+// lock, so callbacks may schedule further events.
+func (c *EventClock) fire() int {
+	c.mu.Lock()
+	key := c.events.Pop()
+	fns := c.pending[key]
+	delete(c.pending, key)
+	c.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+	return len(fns)
+}
@@ -0,0 +1,66 @@
+package heap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualClockAdvanceToNext(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := NewVirtualClock(start)
+
+	var fired []string
+	clock.Schedule(start.Add(3*time.Second), func() { fired = append(fired, "c") })
+	clock.Schedule(start.Add(1*time.Second), func() { fired = append(fired, "a") })
+	clock.Schedule(start.Add(1*time.Second), func() { fired = append(fired, "b") })
+	assert.Equal(t, 3, clock.Len())
+
+	assert.True(t, clock.AdvanceToNext())
+	assert.Equal(t, []string{"a", "b"}, fired)
+	assert.Equal(t, start.Add(time.Second), clock.Now())
+
+	assert.True(t, clock.AdvanceToNext())
+	assert.Equal(t, []string{"a", "b", "c"}, fired)
+	assert.Equal(t, start.Add(3*time.Second), clock.Now())
+
+	assert.False(t, clock.AdvanceToNext(), "AdvanceToNext() on an empty clock returned true")
+	assert.Equal(t, 0, clock.Len())
+}
+
+func TestVirtualClockRunUntil(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+
+	var fired []time.Time
+	record := func() { fired = append(fired, clock.Now()) }
+	clock.Schedule(start.Add(2*time.Second), func() {
+		record()
+		// Events scheduled by callbacks still run if they fall inside the window.
+		clock.Schedule(clock.Now().Add(time.Second), record)
+	})
+	clock.Schedule(start.Add(10*time.Second), record)
+
+	ran := clock.RunUntil(start.Add(5 * time.Second))
+	assert.Equal(t, 2, ran)
+	assert.Equal(t, []time.Time{start.Add(2 * time.Second), start.Add(3 * time.Second)}, fired)
+	assert.Equal(t, start.Add(5*time.Second), clock.Now())
+
+	next, ok := clock.NextAt()
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(10*time.Second), next)
+}
+
+func TestWallClockSleepsUntilEvent(t *testing.T) {
+	clock := NewWallClock()
+	var slept time.Duration
+	clock.sleep = func(d time.Duration) { slept += d }
+
+	fired := false
+	clock.Schedule(time.Now().Add(time.Hour), func() { fired = true })
+
+	assert.True(t, clock.AdvanceToNext())
+	assert.True(t, fired)
+	assert.Greater(t, slept, 59*time.Minute)
+}
@@ -0,0 +1,137 @@
+package heap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrentHeap is a Heap that is safe for use by multiple goroutines.
+// Mutations are serialized by a mutex, while Len and PeekFast read an
+// atomically published size and root so that monitoring and metrics
+// scrapers never contend with writers.
+type ConcurrentHeap[T comparable] struct {
+	mu   sync.Mutex
+	heap *Heap[T]
+	size atomic.Int64      // Size published after every mutation
+	root atomic.Pointer[T] // Root published after every mutation, nil when empty
+
+	generation uint64 // Number of mutations, guarded by mu
+
+	out     chan T        // Channel fed by Out, nil until Out is called
+	changed chan struct{} // Wakes the feeder after a push
+	stop    chan struct{} // Closed by CloseOut to stop the feeder
+}
+
+// NewConcurrentHeap creates a concurrency-safe d-ary heap. The arguments are
+// the same as for NewHeap.
+func NewConcurrentHeap[T comparable](d int, lessFunc func(T, T) bool, options ...Option[T]) *ConcurrentHeap[T] {
+	return &ConcurrentHeap[T]{heap: NewHeap(d, lessFunc, options...)}
+}
+
+// Push adds a new element to the heap.
+func (c *ConcurrentHeap[T]) Push(value T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.heap.Push(value)
+	c.publish()
+	c.notify()
+}
+
+// Pop removes and returns the extremal element from the heap.
+// If the heap is empty, it returns the zero value of type T and false.
+func (c *ConcurrentHeap[T]) Pop() (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.heap.Len()
+	if c.heap.IsEmpty() {
+		if c.heap.Len() != n {
+			c.publish() // Expired elements were discarded
+		}
+		var zero T
+		return zero, false
+	}
+	v := c.heap.Pop()
+	c.publish()
+	return v, true
+}
+
+// Peek returns the extremal element without removing it.
+// If the heap is empty, it returns the zero value of type T and false.
+func (c *ConcurrentHeap[T]) Peek() (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.heap.Len()
+	empty := c.heap.IsEmpty()
+	if c.heap.Len() != n {
+		c.publish() // Expired elements were discarded
+	}
+	if empty {
+		var zero T
+		return zero, false
+	}
+	return c.heap.Peek(), true
+}
+
+// Contains checks if the given element exists in the heap.
+func (c *ConcurrentHeap[T]) Contains(element T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.heap.Contains(element)
+}
+
+// Stats returns the heap's operation counters. See Heap.Stats.
+func (c *ConcurrentHeap[T]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.heap.Stats()
+}
+
+// HeadWait returns how long the extremal element has been queued. See
+// Heap.HeadWait.
+func (c *ConcurrentHeap[T]) HeadWait() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.heap.HeadWait()
+}
+
+// OldestWait returns how long the longest-waiting element has been queued.
+// See Heap.OldestWait.
+func (c *ConcurrentHeap[T]) OldestWait() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.heap.OldestWait()
+}
+
+// Len returns the number of elements in the heap without taking the lock.
+func (c *ConcurrentHeap[T]) Len() int {
+	return int(c.size.Load())
+}
+
+// PeekFast returns the extremal element without taking the lock. The result
+// is best effort: it reflects the most recently completed mutation and may be
+// stale by the time the caller uses it. If the heap is empty, it returns the
+// zero value of type T and false.
+func (c *ConcurrentHeap[T]) PeekFast() (T, bool) {
+	if p := c.root.Load(); p != nil {
+		return *p, true
+	}
+	var zero T
+	return zero, false
+}
+
+// publish advances the generation and stores the size and root for lock-free
+// readers. The root is only republished when it changes, so most pushes do not
+// allocate.
+func (c *ConcurrentHeap[T]) publish() {
+	c.generation++
+	c.size.Store(int64(c.heap.Len()))
+	if c.heap.IsEmpty() {
+		c.root.Store(nil)
+		return
+	}
+	root := c.heap.Peek()
+	if p := c.root.Load(); p == nil || *p != root {
+		c.root.Store(&root)
+	}
+}
@@ -0,0 +1,82 @@
+package heap
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentHeap(t *testing.T) {
+	heap := NewConcurrentHeap[int](4, func(a, b int) bool { return a < b })
+
+	_, ok := heap.PeekFast()
+	assert.False(t, ok)
+
+	const producers, perProducer = 8, 500
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				heap.Push(p*perProducer + i)
+				heap.Len()
+				heap.PeekFast()
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	assert.Equal(t, producers*perProducer, heap.Len())
+	root, ok := heap.PeekFast()
+	assert.True(t, ok)
+	assert.Equal(t, 0, root)
+	assert.True(t, heap.Contains(1234))
+
+	for want := 0; want < producers*perProducer; want++ {
+		v, ok := heap.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, want, v)
+	}
+
+	_, ok = heap.Pop()
+	assert.False(t, ok)
+	_, ok = heap.Peek()
+	assert.False(t, ok)
+	_, ok = heap.PeekFast()
+	assert.False(t, ok)
+	assert.Zero(t, heap.Len())
+}
+
+func TestConcurrentHeapExpired(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	c := NewConcurrentHeap[int](2, func(a, b int) bool { return a < b }, WithTTLClock[int](clock))
+	c.Push(5)
+	c.heap.PushWithTTL(1, time.Second)
+	c.heap.PushWithTTL(2, time.Second)
+	c.publish()
+	assert.Equal(t, 3, c.Len())
+
+	clock.RunUntil(start.Add(time.Second))
+	v, ok := c.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+	assert.Equal(t, 1, c.Len(), "expired elements still published")
+
+	v, ok = c.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+
+	c.heap.PushWithTTL(3, time.Second)
+	clock.RunUntil(start.Add(2 * time.Second))
+	_, ok = c.Pop()
+	assert.False(t, ok, "expired element popped")
+	_, ok = c.Peek()
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
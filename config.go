@@ -0,0 +1,86 @@
+package heap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Config declares a heap configuration as a plain value, as an alternative to
+// passing functional options to NewHeap. It can be built from configuration
+// files or tables and is validated in one place by New. Insertion-order
+// stability is not a field: it is provided by StableHeap, a separate type
+// created with NewStableHeap.
+type Config[T comparable] struct {
+	D        int             // Branching factor, at least 2
+	Capacity int             // Initial capacity, or zero for the default
+	Less     func(T, T) bool // Function to determine order, required
+	Lazy     bool            // Defer heap order until the first read, see WithLazyHeapify
+	Strategy SiftStrategy    // Sift-down strategy, see WithSiftStrategy
+	Indexing Indexing        // How element positions are recorded
+	Hash     func(T) uint64  // Key the index by hash, see WithHashIndex
+	Options  []Option[T]     // Further options applied after the fields above
+}
+
+// Indexing selects how a heap created from a Config records the position of
+// each element.
+type Indexing int
+
+const (
+	// IndexValues records positions keyed by element value, or by hash when
+	// Config.Hash is set. It is the default.
+	IndexValues Indexing = iota
+	// IndexNone records no positions, see WithoutIndex.
+	IndexNone
+)
+
+// Validate reports whether the configuration describes a usable heap.
+func (c Config[T]) Validate() error {
+	if c.D < 2 {
+		return fmt.Errorf("%w: must be at least 2, got %d", ErrInvalidBranchingFactor, c.D)
+	}
+	if c.Capacity < 0 {
+		return fmt.Errorf("heap: capacity must not be negative, got %d", c.Capacity)
+	}
+	if c.Less == nil {
+		return errors.New("heap: less function is required")
+	}
+	if c.Strategy != SiftStandard && c.Strategy != SiftBottomUp {
+		return fmt.Errorf("heap: unknown sift strategy %d", c.Strategy)
+	}
+	if c.Indexing != IndexValues && c.Indexing != IndexNone {
+		return fmt.Errorf("heap: unknown indexing %d", c.Indexing)
+	}
+	if c.Indexing == IndexNone && c.Hash != nil {
+		return errors.New("heap: hash index requires IndexValues")
+	}
+	return nil
+}
+
+// New creates a heap from cfg, returning an error if cfg is invalid.
+func New[T comparable](cfg Config[T]) (*Heap[T], error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return NewHeap(cfg.D, cfg.Less, cfg.options()...), nil
+}
+
+// options translates the configuration into the equivalent functional options.
+func (c Config[T]) options() []Option[T] {
+	var opts []Option[T]
+	if c.Indexing == IndexNone {
+		opts = append(opts, WithoutIndex[T]())
+	}
+	if c.Hash != nil {
+		opts = append(opts, WithHashIndex(c.Hash))
+	}
+	if c.Capacity > 0 {
+		opts = append(opts, WithCapacity[T](c.Capacity))
+	}
+	if c.Lazy {
+		opts = append(opts, WithLazyHeapify[T]())
+	}
+	if c.Strategy != SiftStandard {
+		opts = append(opts, WithSiftStrategy[T](c.Strategy))
+	}
+	return append(opts, c.Options...)
+}
@@ -0,0 +1,60 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tests := []struct {
+		name    string
+		cfg     Config[int]
+		wantErr string
+	}{
+		{name: "Minimal", cfg: Config[int]{D: 2, Less: less}},
+		{
+			name: "All fields",
+			cfg: Config[int]{
+				D: 4, Capacity: 32, Less: less, Lazy: true, Strategy: SiftBottomUp,
+				Hash:    func(v int) uint64 { return uint64(v) },
+				Options: []Option[int]{WithSiftProfile[int](nil)},
+			},
+		},
+		{name: "Branching factor too small", cfg: Config[int]{D: 1, Less: less}, wantErr: "heap: invalid branching factor: must be at least 2, got 1"},
+		{name: "Negative capacity", cfg: Config[int]{D: 2, Capacity: -1, Less: less}, wantErr: "heap: capacity must not be negative, got -1"},
+		{name: "Missing less", cfg: Config[int]{D: 2}, wantErr: "heap: less function is required"},
+		{name: "Unknown strategy", cfg: Config[int]{D: 2, Less: less, Strategy: 9}, wantErr: "heap: unknown sift strategy 9"},
+		{name: "Without index", cfg: Config[int]{D: 3, Less: less, Indexing: IndexNone, Capacity: 8}},
+		{name: "Unknown indexing", cfg: Config[int]{D: 2, Less: less, Indexing: 5}, wantErr: "heap: unknown indexing 5"},
+		{
+			name:    "Hash without index",
+			cfg:     Config[int]{D: 2, Less: less, Indexing: IndexNone, Hash: func(v int) uint64 { return uint64(v) }},
+			wantErr: "heap: hash index requires IndexValues",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			heap, err := New(tt.cfg)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, heap)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.cfg.Indexing == IndexNone, heap.noIndex)
+
+			for _, v := range []int{5, 1, 4, 2, 3} {
+				heap.Push(v)
+			}
+			for want := 1; want <= 5; want++ {
+				assert.Equal(t, want, heap.Pop())
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package heap
+
+import (
+	"maps"
+	"time"
+)
+
+// CopyFrom clears h and fills it with the elements of src, keeping h's own
+// branching factor, comparator and options. Because the two comparators
+// cannot be compared, the copied elements are always re-heapified, which is
+// O(n) and performs no swaps when they are already in h's order. This lets a
+// pooled heap be seeded from a template without element-by-element pushes.
+func (h *Heap[T]) CopyFrom(src *Heap[T]) {
+	if h == src {
+		return
+	}
+	src.ensureHeap()
+	h.reset()
+	h.inheritTTL(src)
+	h.load(src.data[:src.heapSize])
+	if h.wait != nil && src.wait != nil {
+		copy(h.wait.enqueued, src.wait.enqueued[:src.heapSize])
+	}
+	if h.ttl != nil && src.ttl != nil {
+		copy(h.ttl.expires, src.ttl.expires[:src.heapSize])
+	}
+	h.heapify()
+	h.trim()
+}
+
+// Clone returns an independent copy of the heap with the same elements,
+// branching factor, comparator and options, so it can be drained
+// speculatively while h is kept. Recorded statistics such as wait times and
+// sift profiles are copied too. The clone does not inherit h's operation log,
+// since its mutations are not h's, and handles from PushHandle keep referring
+// to elements of h.
+func (h *Heap[T]) Clone() *Heap[T] {
+	c := *h
+	c.data = append([]T(nil), h.data[:h.heapSize]...)
+	switch {
+	case h.noIndex:
+	case h.keyed != nil:
+		c.keyed = h.keyed.clone()
+	case h.hash != nil:
+		c.hashed = cloneIndex(h.hashed)
+	default:
+		c.index = cloneIndex(h.index)
+	}
+	c.spare, c.path, c.items = nil, nil, nil
+	c.tombs = maps.Clone(h.tombs)
+	c.oplog, c.opSeq = nil, 0
+	if h.wait != nil {
+		wait := *h.wait
+		wait.enqueued = append([]time.Time(nil), h.wait.enqueued[:h.heapSize]...)
+		wait.waits.samples = append([]time.Duration(nil), h.wait.waits.samples...)
+		c.wait = &wait
+	}
+	if h.ttl != nil {
+		ttl := *h.ttl
+		ttl.expires = append([]time.Time(nil), h.ttl.expires[:h.heapSize]...)
+		c.ttl = &ttl
+	}
+	if h.agg != nil {
+		agg := *h.agg
+		c.agg = &agg
+	}
+	if h.adaptive != nil {
+		adaptive := *h.adaptive
+		c.adaptive = &adaptive
+	}
+	if h.profile != nil {
+		profile := *h.profile
+		profile.profile.Up = append([]int(nil), h.profile.profile.Up...)
+		profile.profile.Down = append([]int(nil), h.profile.profile.Down...)
+		c.profile = &profile
+	}
+	if h.occupancy != nil {
+		occupancy := *h.occupancy
+		c.occupancy = &occupancy
+	}
+	if h.stats != nil {
+		stats := *h.stats
+		c.stats = &stats
+	}
+	if h.shrink != nil {
+		shrink := *h.shrink
+		c.shrink = &shrink
+	}
+	return &c
+}
+
+// cloneIndex returns a deep copy of index.
+func cloneIndex[K comparable](index map[K][]int) map[K][]int {
+	cloned := make(map[K][]int, len(index))
+	for k, indices := range index {
+		cloned[k] = append([]int(nil), indices...)
+	}
+	return cloned
+}
+
+// Meld moves every element of other into h and leaves other empty. Both heaps
+// must order elements the same way. It costs O(m log n) for m moved elements
+// when other is small relative to h and at most O(n + m) otherwise, and
+// merges other's index into h's. Enqueue and expiry times move with their
+// elements; handles into other are invalidated.
+func (h *Heap[T]) Meld(other *Heap[T]) {
+	if h == other {
+		return
+	}
+	other.purge()
+	start, n := h.heapSize, other.heapSize
+	h.inheritTTL(other)
+	h.load(other.data[:n])
+	if h.wait != nil && other.wait != nil {
+		copy(h.wait.enqueued[start:], other.wait.enqueued[:n])
+	}
+	if h.ttl != nil && other.ttl != nil {
+		copy(h.ttl.expires[start:], other.ttl.expires[:n])
+	}
+	h.mergeLoaded(n)
+	other.reset()
+	h.trim()
+}
+
+// reset empties the heap while keeping its allocated storage.
+func (h *Heap[T]) reset() {
+	if h.items != nil {
+		h.releaseAll()
+	}
+	h.vacate(0, len(h.data))
+	h.heapSize = 0
+	h.dirty = false
+	h.staged = 0
+	clear(h.tombs)
+	h.dead = 0
+	h.clearIndex()
+	if h.wait != nil {
+		h.wait.enqueued = h.wait.enqueued[:0]
+	}
+	if h.ttl != nil {
+		h.ttl.expires = h.ttl.expires[:0]
+	}
+	if h.agg != nil {
+		var zero T
+		h.agg.sum, h.agg.max = 0, zero
+	}
+	if h.oplog != nil {
+		h.log(Op[T]{Kind: OpReset})
+	}
+}
+
+// load appends values to the heap without restoring the heap property,
+// recording their positions, enqueue times and aggregates. The caller must
+// restore the heap property afterwards.
+func (h *Heap[T]) load(values []T) {
+	for _, v := range values {
+		if len(h.data) == h.heapSize {
+			h.data = append(h.data, v)
+		} else {
+			h.data[h.heapSize] = v
+		}
+		h.addIndex(v, h.heapSize)
+		if h.wait != nil {
+			h.wait.stamp(h.heapSize)
+		}
+		if h.ttl != nil {
+			h.ttl.stamp(h.heapSize)
+		}
+		if h.items != nil {
+			h.trackSlot(h.heapSize, nil)
+		}
+		h.heapSize++
+		if h.stats != nil {
+			h.stats.pushed(h.heapSize)
+		}
+		if h.agg != nil {
+			h.agg.added(h, v)
+		}
+		if h.oplog != nil {
+			h.log(Op[T]{Kind: OpPush, Value: v})
+		}
+		if h.hooks != nil {
+			h.hooks.pushed(v)
+		}
+	}
+	if h.heapSize > h.highWater {
+		h.highWater = h.heapSize
+	}
+}
+
+// Swap exchanges the contents of h and other in O(1), leaving each heap with
+// its own branching factor, comparator and options. It enables double
+// buffering: drain the old contents while a fresh heap accepts new work.
+//
+// Both heaps must order elements the same way. If their branching factors
+// differ, both are re-heapified in O(n) on their next read, and if only one
+// of them tracks waits or aggregates, that state is rebuilt in O(n). Each
+// heap keeps its own index configuration, so unless both use the default
+// index or both use WithoutIndex, both indexes are rebuilt in O(n). Handles
+// from PushHandle follow their elements to the other heap in O(n).
+func (h *Heap[T]) Swap(other *Heap[T]) {
+	if h == other {
+		return
+	}
+	h.unstage()
+	other.unstage()
+	h.purge()
+	other.purge()
+	h.inheritTTL(other)
+	other.inheritTTL(h)
+	h.data, other.data = other.data, h.data
+	h.heapSize, other.heapSize = other.heapSize, h.heapSize
+	h.dirty, other.dirty = other.dirty, h.dirty
+	switch {
+	case h.noIndex && other.noIndex:
+	case h.valueIndexed() && other.valueIndexed():
+		h.index, other.index = other.index, h.index
+		h.spare, other.spare = other.spare, h.spare
+	default:
+		h.rebuildIndex()
+		other.rebuildIndex()
+	}
+	h.items, other.items = other.items, h.items
+	h.adoptItems()
+	other.adoptItems()
+
+	switch {
+	case h.wait != nil && other.wait != nil:
+		h.wait.enqueued, other.wait.enqueued = other.wait.enqueued, h.wait.enqueued
+	case h.wait != nil:
+		h.wait.restamp(h.heapSize)
+	case other.wait != nil:
+		other.wait.restamp(other.heapSize)
+	}
+	if h.ttl != nil {
+		h.ttl.expires, other.ttl.expires = other.ttl.expires, h.ttl.expires
+	}
+	if h.agg != nil && other.agg != nil {
+		h.agg.sum, other.agg.sum = other.agg.sum, h.agg.sum
+		h.agg.max, other.agg.max = other.agg.max, h.agg.max
+	} else if h.agg != nil {
+		h.agg.recompute(h)
+	} else if other.agg != nil {
+		other.agg.recompute(other)
+	}
+	if h.d != other.d {
+		h.dirty, other.dirty = true, true
+	}
+	h.highWater = max(h.highWater, h.heapSize)
+	other.highWater = max(other.highWater, other.heapSize)
+	h.logContents()
+	other.logContents()
+	h.trim()
+	other.trim()
+}
+
+// logContents logs the heap's contents as a reset followed by a push of every
+// element, for operations that replace the contents wholesale.
+func (h *Heap[T]) logContents() {
+	if h.oplog == nil {
+		return
+	}
+	h.log(Op[T]{Kind: OpReset})
+	for _, v := range h.data[:h.heapSize] {
+		h.log(Op[T]{Kind: OpPush, Value: v})
+	}
+}
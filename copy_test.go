@@ -0,0 +1,150 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapCopyFrom(t *testing.T) {
+	src := NewHeap[int](2, func(a, b int) bool { return a < b })
+	for _, v := range []int{5, 3, 8, 1, 9, 3} {
+		src.Push(v)
+	}
+
+	// A pooled max-heap with leftover elements is reseeded from the min-heap.
+	dst := NewHeap[int](3, func(a, b int) bool { return a > b },
+		WithAggregates[int](func(v int) float64 { return float64(v) }))
+	dst.Push(100)
+	dst.Push(200)
+
+	dst.CopyFrom(src)
+	assert.Equal(t, 6, dst.heapSize)
+	assert.False(t, dst.Contains(100))
+	assert.True(t, dst.Contains(3))
+	assert.Equal(t, Aggregates[int]{Count: 6, Sum: 29, Min: 9, Max: 1}, dst.Aggregates())
+
+	for _, want := range []int{9, 8, 5, 3, 3, 1} {
+		assert.Equal(t, want, dst.Pop())
+	}
+	assert.Empty(t, dst.index)
+
+	// The source is unchanged.
+	assert.Equal(t, 6, src.heapSize)
+	assert.Equal(t, 1, src.Peek())
+}
+
+func TestHeapSwap(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	draining := NewHeap[int](2, less)
+	accepting := NewHeap[int](4, less, WithAggregates[int](func(v int) float64 { return float64(v) }))
+	for _, v := range []int{4, 2, 6} {
+		draining.Push(v)
+	}
+	for _, v := range []int{5, 1, 3, 7, 0} {
+		accepting.Push(v)
+	}
+
+	draining.Swap(accepting)
+	assert.Equal(t, 5, draining.heapSize)
+	assert.Equal(t, 3, accepting.heapSize)
+	assert.True(t, draining.Contains(7))
+	assert.False(t, draining.Contains(6))
+	assert.Equal(t, Aggregates[int]{Count: 3, Sum: 12, Min: 2, Max: 6}, accepting.Aggregates())
+
+	for _, want := range []int{0, 1, 3, 5, 7} {
+		assert.Equal(t, want, draining.Pop())
+	}
+	for _, want := range []int{2, 4, 6} {
+		assert.Equal(t, want, accepting.Pop())
+	}
+}
+
+func TestHeapSwapIndexModes(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	hash := func(v int) uint64 { return uint64(v) }
+	identity := func(v int) int { return v % 100 }
+	modes := map[string]func() *Heap[int]{
+		"value":    func() *Heap[int] { return NewHeap[int](2, less) },
+		"none":     func() *Heap[int] { return NewHeap[int](2, less, WithoutIndex[int]()) },
+		"hash":     func() *Heap[int] { return NewHeap[int](2, less, WithHashIndex[int](hash)) },
+		"identity": func() *Heap[int] { return NewHeap[int](2, less, WithIdentity[int](identity)) },
+	}
+
+	for nameA, newA := range modes {
+		for nameB, newB := range modes {
+			a, b := newA(), newB()
+			a.PushAll(1, 2, 3)
+			b.PushAll(10, 20)
+			a.Swap(b)
+
+			for name, pair := range map[string]struct{ got, want *Heap[int] }{nameA: {a, newA()}, nameB: {b, newB()}} {
+				assert.Equal(t, pair.want.noIndex, pair.got.noIndex, "%s<->%s: %s heap changed index mode", nameA, nameB, name)
+				assert.Equal(t, pair.want.hash == nil, pair.got.hash == nil, "%s<->%s: %s heap changed index mode", nameA, nameB, name)
+				assert.Equal(t, pair.want.keyed == nil, pair.got.keyed == nil, "%s<->%s: %s heap changed index mode", nameA, nameB, name)
+				assert.NoError(t, pair.got.Verify(), "%s<->%s: %s heap", nameA, nameB, name)
+			}
+			assert.True(t, a.Contains(20))
+			assert.False(t, a.Contains(1))
+			assert.True(t, b.Contains(3))
+			assert.True(t, b.Remove(2))
+			assert.Equal(t, []int{1, 3}, b.PopN(2))
+		}
+	}
+}
+
+func TestHeapMeld(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	shards := make([]*Heap[int], 4)
+	for s := range shards {
+		shards[s] = NewHeap[int](2, less)
+		for i := s; i < 40; i += len(shards) {
+			shards[s].Push(i)
+		}
+	}
+
+	joined := NewHeap[int](4, less, WithAggregates[int](func(v int) float64 { return float64(v) }))
+	for _, shard := range shards {
+		joined.Meld(shard)
+		assert.True(t, shard.IsEmpty())
+		assert.Empty(t, shard.index)
+	}
+	joined.Meld(joined)
+
+	assert.Equal(t, 40, joined.Len())
+	assert.Equal(t, float64(780), joined.Aggregates().Sum)
+	assert.True(t, joined.Contains(37))
+	for want := 0; want < 40; want++ {
+		assert.Equal(t, want, joined.Pop())
+	}
+	assert.Empty(t, joined.index)
+}
+
+func TestHeapClone(t *testing.T) {
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b },
+		WithAggregates[int](func(v int) float64 { return float64(v) }),
+		WithSiftProfile[int](nil))
+	for _, v := range []int{6, 2, 9, 2, 5} {
+		heap.Push(v)
+	}
+
+	clone := heap.Clone()
+	for _, want := range []int{2, 2, 5, 6, 9} {
+		assert.Equal(t, want, clone.Pop())
+	}
+	clone.Push(1)
+	assert.Equal(t, Aggregates[int]{Count: 1, Sum: 1, Min: 1, Max: 1}, clone.Aggregates())
+
+	// The original is untouched by the speculative drain.
+	assert.Equal(t, 5, heap.Len())
+	assert.False(t, heap.Contains(1))
+	assert.True(t, heap.Contains(9))
+	assert.Equal(t, Aggregates[int]{Count: 5, Sum: 24, Min: 2, Max: 9}, heap.Aggregates())
+	assert.Len(t, heap.SiftProfile().Down, 0)
+	for _, want := range []int{2, 2, 5, 6, 9} {
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.Empty(t, heap.index)
+}
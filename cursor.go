@@ -0,0 +1,73 @@
+package heap
+
+// Cursor pages through a snapshot of a ConcurrentHeap in heap order. The
+// snapshot is pinned to the generation at which the cursor was opened: pushes
+// and pops that happen afterwards are not reflected, and they never wait for
+// the cursor. A cursor can be paused between pages for as long as needed and
+// resumed later, which suits admin tooling that browses a large live queue.
+//
+// Opening a cursor copies the elements under the heap's lock in O(n); each
+// element is then produced in O(log n) as pages are read, so a caller that
+// only looks at the first few pages does not pay for a full sort. A Cursor is
+// not safe for concurrent use.
+type Cursor[T any] struct {
+	remaining  []T // Private copy of the elements not yet returned, in heap order
+	d          int
+	less       func(T, T) bool
+	generation uint64
+	pos        int // Number of elements returned so far
+}
+
+// Cursor opens a cursor over the current contents of the heap.
+func (c *ConcurrentHeap[T]) Cursor() *Cursor[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.heap.ensureHeap()
+	return &Cursor[T]{
+		remaining:  append([]T(nil), c.heap.data[:c.heap.heapSize]...),
+		d:          c.heap.d,
+		less:       c.heap.lessFunc,
+		generation: c.generation,
+	}
+}
+
+// Generation returns the number of mutations applied to the heap so far.
+// Comparing it with a cursor's Generation tells how far the live heap has
+// moved on since the cursor was opened.
+func (c *ConcurrentHeap[T]) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// Generation returns the generation of the heap the cursor's snapshot was
+// taken at.
+func (cur *Cursor[T]) Generation() uint64 {
+	return cur.generation
+}
+
+// Pos returns the number of elements the cursor has returned so far.
+func (cur *Cursor[T]) Pos() int {
+	return cur.pos
+}
+
+// Remaining returns the number of elements the cursor has yet to return.
+func (cur *Cursor[T]) Remaining() int {
+	return len(cur.remaining)
+}
+
+// Next returns up to n of the next elements of the snapshot in heap order. It
+// returns an empty slice once the snapshot is exhausted or if n is not
+// positive.
+func (cur *Cursor[T]) Next(n int) []T {
+	n = max(min(n, len(cur.remaining)), 0)
+	page := make([]T, 0, n)
+	for range n {
+		size := len(cur.remaining)
+		page = append(page, cur.remaining[0])
+		popSlice(cur.remaining, size, cur.d, cur.less)
+		cur.remaining = cur.remaining[:size-1]
+	}
+	cur.pos += n
+	return page
+}
@@ -0,0 +1,68 @@
+package heap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	t.Parallel()
+
+	heap := NewConcurrentHeap[int](3, func(a, b int) bool { return a < b })
+	for _, v := range []int{8, 3, 5, 1, 9, 2, 7} {
+		heap.Push(v)
+	}
+
+	cursor := heap.Cursor()
+	assert.Equal(t, uint64(7), cursor.Generation())
+	assert.Equal(t, []int{1, 2, 3}, cursor.Next(3))
+	assert.Empty(t, cursor.Next(-1))
+	assert.Empty(t, cursor.Next(0))
+	assert.Equal(t, 3, cursor.Pos())
+
+	// Mutations after the cursor is opened do not affect it.
+	heap.Push(0)
+	heap.Pop()
+	heap.Pop()
+	assert.Equal(t, uint64(10), heap.Generation())
+
+	assert.Equal(t, []int{5, 7, 8}, cursor.Next(3))
+	assert.Equal(t, 6, cursor.Pos())
+	assert.Equal(t, []int{9}, cursor.Next(3))
+	assert.Empty(t, cursor.Next(3))
+	assert.Zero(t, cursor.Remaining())
+	assert.Equal(t, 6, heap.Len())
+}
+
+func TestCursorConcurrentPushes(t *testing.T) {
+	t.Parallel()
+
+	heap := NewConcurrentHeap[int](4, func(a, b int) bool { return a < b })
+	for i := 0; i < 1000; i++ {
+		heap.Push(i)
+	}
+	cursor := heap.Cursor()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			heap.Push(-i)
+		}
+	}()
+
+	var got []int
+	for page := cursor.Next(64); len(page) > 0; page = cursor.Next(64) {
+		got = append(got, page...)
+	}
+	wg.Wait()
+
+	assert.Len(t, got, 1000)
+	for i, v := range got {
+		assert.Equal(t, i, v)
+	}
+	assert.Equal(t, 2000, heap.Len())
+}
@@ -0,0 +1,59 @@
+package heap
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ToDOT writes the heap as a Graphviz DOT digraph with one node per element
+// and an edge from every element to each of its children, so the tree can be
+// rendered with dot -Tsvg. Nodes are labelled with label(element), or with
+// fmt.Sprint(element) if label is nil.
+func (h *Heap[T]) ToDOT(w io.Writer, label func(T) string) error {
+	h.ensureHeap()
+	if label == nil {
+		label = func(v T) string { return fmt.Sprint(v) }
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph heap {\n\t// d=%d n=%d\n\tnode [shape=box];\n", h.d, h.heapSize)
+	for i, v := range h.data[:h.heapSize] {
+		fmt.Fprintf(&b, "\tn%d [label=%s];\n", i, strconv.Quote(label(v)))
+		if i > 0 {
+			fmt.Fprintf(&b, "\tn%d -> n%d;\n", h.parent(i), i)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Dump writes the heap as an indented tree, one element per line with each
+// child indented two spaces beyond its parent and children listed in array
+// order. Unlike printing the backing slice, this shows which elements are
+// siblings under the heap's branching factor.
+func (h *Heap[T]) Dump(w io.Writer) error {
+	h.ensureHeap()
+	var b strings.Builder
+	if h.heapSize > 0 {
+		h.dumpNode(&b, 0, 0)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dumpNode writes the subtree rooted at index i at the given depth.
+func (h *Heap[T]) dumpNode(b *strings.Builder, i, depth int) {
+	fmt.Fprintf(b, "%s%v\n", strings.Repeat("  ", depth), h.data[i])
+	for k := 1; k <= h.d && h.child(i, k) < h.heapSize; k++ {
+		h.dumpNode(b, h.child(i, k), depth+1)
+	}
+}
+
+// String returns the indented tree written by Dump.
+func (h *Heap[T]) String() string {
+	var b strings.Builder
+	h.Dump(&b)
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package heap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapToDOT(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b })
+	for _, v := range []int{4, 1, 3, 2, 5} {
+		heap.Push(v)
+	}
+
+	var b strings.Builder
+	assert.NoError(t, heap.ToDOT(&b, func(v int) string { return "p" + strconv.Itoa(v) }))
+	want := `digraph heap {
+	// d=3 n=5
+	node [shape=box];
+	n0 [label="p1"];
+	n1 [label="p4"];
+	n0 -> n1;
+	n2 [label="p3"];
+	n0 -> n2;
+	n3 [label="p2"];
+	n0 -> n3;
+	n4 [label="p5"];
+	n1 -> n4;
+}
+`
+	assert.Equal(t, want, b.String())
+
+	quoted := NewHeap[string](2, func(a, b string) bool { return a < b })
+	quoted.Push(`say "hi"`)
+	b.Reset()
+	assert.NoError(t, quoted.ToDOT(&b, nil))
+	assert.Contains(t, b.String(), `n0 [label="say \"hi\""];`)
+
+	assert.ErrorIs(t, heap.ToDOT(failingWriter{}, nil), errWrite)
+}
+
+var errWrite = errors.New("write failed")
+
+// failingWriter is an io.Writer whose writes always fail.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func TestHeapDump(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	assert.Empty(t, heap.String())
+	for _, v := range []int{6, 2, 5, 1, 4, 3} {
+		heap.Push(v)
+	}
+
+	want := `1
+  2
+    6
+    4
+  3
+    5
+`
+	assert.Equal(t, want, heap.String())
+	assert.Equal(t, want, fmt.Sprint(heap))
+	assert.ErrorIs(t, heap.Dump(failingWriter{}), errWrite)
+}
@@ -0,0 +1,106 @@
+package heap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DelayQueue is a concurrency-safe queue whose elements become available
+// only once their ready time has passed, for retry and backoff systems.
+// Elements with the same ready time are released in the order they were
+// pushed.
+type DelayQueue[T any] struct {
+	mu      sync.Mutex
+	heap    *StableHeap[delayed[T]]
+	changed chan struct{} // Closed and replaced when the earliest ready time moves earlier
+}
+
+// delayed is an element together with the time it becomes ready.
+type delayed[T any] struct {
+	value T
+	at    time.Time
+}
+
+// NewDelayQueue creates an empty delay queue backed by a d-ary heap.
+func NewDelayQueue[T any](d int) *DelayQueue[T] {
+	return &DelayQueue[T]{
+		heap:    NewStableHeap[delayed[T]](d, func(a, b delayed[T]) bool { return a.at.Before(b.at) }),
+		changed: make(chan struct{}),
+	}
+}
+
+// Len returns the number of elements in the queue, ready or not.
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.heap.Len()
+}
+
+// Push adds value to the queue, to become ready at the given time. If it is
+// now the earliest element, goroutines blocked in Pop re-arm their timers.
+func (q *DelayQueue[T]) Push(value T, at time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	earliest := q.heap.Len() == 0 || at.Before(q.heap.Peek().at)
+	q.heap.Push(delayed[T]{value: value, at: at})
+	if earliest {
+		close(q.changed)
+		q.changed = make(chan struct{})
+	}
+}
+
+// TryPop removes and returns the earliest element if it is ready, without
+// blocking. It reports false if the queue is empty or nothing is ready yet.
+func (q *DelayQueue[T]) TryPop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.heap.Len() == 0 || time.Now().Before(q.heap.Peek().at) {
+		var zero T
+		return zero, false
+	}
+	return q.heap.Pop().value, true
+}
+
+// Pop removes and returns the earliest element, blocking until its ready
+// time has passed. If an earlier element is pushed while it waits, Pop waits
+// for that one instead. It returns ctx's error if ctx is done first.
+func (q *DelayQueue[T]) Pop(ctx context.Context) (T, error) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		q.mu.Lock()
+		changed := q.changed
+		var wait time.Duration
+		if q.heap.Len() > 0 {
+			if wait = time.Until(q.heap.Peek().at); wait <= 0 {
+				v := q.heap.Pop().value
+				q.mu.Unlock()
+				return v, nil
+			}
+		}
+		q.mu.Unlock()
+
+		var ready <-chan time.Time
+		if wait > 0 {
+			if timer == nil {
+				timer = time.NewTimer(wait)
+			} else {
+				timer.Reset(wait)
+			}
+			ready = timer.C
+		}
+		select {
+		case <-ready:
+		case <-changed:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
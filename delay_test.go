@@ -0,0 +1,97 @@
+package heap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelayQueueOrder(t *testing.T) {
+	t.Parallel()
+
+	q := NewDelayQueue[string](4)
+	past := time.Now().Add(-time.Minute)
+	q.Push("c", past.Add(2*time.Second))
+	q.Push("a", past)
+	q.Push("b", past.Add(time.Second))
+	q.Push("b2", past.Add(time.Second))
+	q.Push("later", time.Now().Add(time.Hour))
+	assert.Equal(t, 5, q.Len())
+
+	var got []string
+	for {
+		v, ok := q.TryPop()
+		if !ok {
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []string{"a", "b", "b2", "c"}, got, "ready elements in time order, ties first in first out")
+	assert.Equal(t, 1, q.Len(), "later is not ready")
+}
+
+func TestDelayQueuePopBlocks(t *testing.T) {
+	t.Parallel()
+
+	q := NewDelayQueue[int](2)
+	start := time.Now()
+	q.Push(1, start.Add(20*time.Millisecond))
+
+	v, err := q.Pop(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestDelayQueueEarlierPush(t *testing.T) {
+	t.Parallel()
+
+	q := NewDelayQueue[int](2)
+	q.Push(1, time.Now().Add(time.Hour))
+
+	done := make(chan int)
+	go func() {
+		v, err := q.Pop(context.Background())
+		assert.NoError(t, err)
+		done <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+	q.Push(2, time.Now().Add(10*time.Millisecond))
+
+	select {
+	case v := <-done:
+		assert.Equal(t, 2, v)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Pop did not re-arm for the earlier element")
+	}
+	assert.Equal(t, 1, q.Len())
+}
+
+func TestDelayQueueEmptyPush(t *testing.T) {
+	t.Parallel()
+
+	q := NewDelayQueue[int](2)
+	done := make(chan int)
+	go func() {
+		v, _ := q.Pop(context.Background())
+		done <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+	q.Push(7, time.Now())
+	assert.Equal(t, 7, <-done)
+}
+
+func TestDelayQueuePopCanceled(t *testing.T) {
+	t.Parallel()
+
+	q := NewDelayQueue[int](2)
+	q.Push(1, time.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := q.Pop(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, q.Len())
+}
@@ -0,0 +1,87 @@
+package heap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// encodedHeap is the serialized form of a heap.
+type encodedHeap[T any] struct {
+	D        int `json:"d"`
+	Elements []T `json:"elements"`
+}
+
+// MarshalJSON encodes the branching factor and the elements of the heap, in
+// heap order, as {"d": d, "elements": [...]}. The comparator and options are
+// not encoded.
+func (h *Heap[T]) MarshalJSON() ([]byte, error) {
+	h.ensureHeap()
+	return json.Marshal(encodedHeap[T]{D: h.d, Elements: h.data[:h.heapSize]})
+}
+
+// UnmarshalJSON replaces the contents of the heap with the encoded elements
+// and adopts the encoded branching factor, rebuilding the heap and its index.
+// The heap keeps its own comparator and options. A zero Heap can be decoded
+// into as well, but must be given a comparator with SetLess before use.
+func (h *Heap[T]) UnmarshalJSON(data []byte) error {
+	var decoded encodedHeap[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	return h.restore(decoded.D, decoded.Elements)
+}
+
+// GobEncode encodes the branching factor and the elements of the heap for
+// encoding/gob, so a heap embedded in a larger struct survives a round trip.
+// The comparator and options are not encoded.
+func (h *Heap[T]) GobEncode() ([]byte, error) {
+	h.ensureHeap()
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(encodedHeap[T]{D: h.d, Elements: h.data[:h.heapSize]})
+	return buf.Bytes(), err
+}
+
+// GobDecode replaces the contents of the heap with the encoded elements and
+// adopts the encoded branching factor. gob decodes into zero values, so the
+// heap usually has no comparator yet; the heap property is then restored when
+// one is attached with SetLess.
+func (h *Heap[T]) GobDecode(data []byte) error {
+	var decoded encodedHeap[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+	return h.restore(decoded.D, decoded.Elements)
+}
+
+// SetLess sets the comparator of the heap and restores the heap property
+// under it in O(n). It attaches a comparator to a heap decoded into a zero
+// value, and can also re-order a heap under a new ordering.
+func (h *Heap[T]) SetLess(lessFunc func(T, T) bool) {
+	h.lessFunc = lessFunc
+	h.dirty, h.staged = false, 0
+	h.heapify()
+	h.trim()
+}
+
+// restore replaces the contents of the heap with elements and sets its
+// branching factor to d, rebuilding the index. The heap property is restored
+// immediately if the heap has a comparator and by SetLess otherwise.
+func (h *Heap[T]) restore(d int, elements []T) error {
+	if d < 2 {
+		return fmt.Errorf("%w: must be at least 2, got %d", ErrInvalidBranchingFactor, d)
+	}
+	if h.index == nil && h.hash == nil && h.keyed == nil {
+		h.newIndex(len(elements))
+	}
+	h.reset()
+	h.setBranching(d)
+	h.load(elements)
+	if h.lessFunc == nil {
+		return nil
+	}
+	h.heapify()
+	h.trim()
+	return nil
+}
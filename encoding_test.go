@@ -0,0 +1,106 @@
+package heap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapJSON(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	heap := NewHeap[int](3, less)
+	for _, v := range []int{5, 2, 8, 2, 7} {
+		heap.Push(v)
+	}
+
+	data, err := json.Marshal(heap)
+	assert.NoError(t, err)
+
+	restored := NewHeap[int](2, less)
+	restored.Push(100)
+	assert.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, 3, restored.BranchingFactor())
+	assert.False(t, restored.Contains(100))
+	assert.True(t, restored.Contains(7))
+	for _, want := range []int{2, 2, 5, 7, 8} {
+		assert.Equal(t, want, restored.Pop())
+	}
+	assert.Empty(t, restored.index)
+}
+
+func TestHeapJSONWithoutComparator(t *testing.T) {
+	t.Parallel()
+
+	var heap Heap[int]
+	assert.NoError(t, json.Unmarshal([]byte(`{"d":4,"elements":[3,9,1,4]}`), &heap))
+	heap.SetLess(func(a, b int) bool { return a > b })
+	assert.Equal(t, []int{9, 4, 3, 1}, heap.PopN(4))
+}
+
+func TestHeapGob(t *testing.T) {
+	t.Parallel()
+
+	type scheduler struct {
+		Name  string
+		Queue *Heap[string]
+	}
+	less := func(a, b string) bool { return a < b }
+	in := scheduler{Name: "jobs", Queue: NewHeap[string](3, less)}
+	for _, v := range []string{"m", "c", "x", "a"} {
+		in.Queue.Push(v)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(in))
+	var out scheduler
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+	out.Queue.SetLess(less)
+
+	assert.Equal(t, "jobs", out.Name)
+	assert.Equal(t, 3, out.Queue.BranchingFactor())
+	assert.True(t, out.Queue.Contains("x"))
+	assert.Equal(t, []string{"a", "c", "m", "x"}, out.Queue.PopN(4))
+	assert.Empty(t, out.Queue.index)
+}
+
+func TestHeapSetLess(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	heap.PushAll(4, 1, 3, 2)
+	heap.SetLess(func(a, b int) bool { return a > b })
+	assert.Equal(t, []int{4, 3, 2, 1}, heap.PopN(4))
+}
+
+func TestHeapUnmarshalJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		heap *Heap[int]
+		data string
+		want error
+	}{
+		{name: "invalid branching factor", heap: NewHeap[int](2, func(a, b int) bool { return a < b }), data: `{"d":1,"elements":[1]}`, want: ErrInvalidBranchingFactor},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := json.Unmarshal([]byte(tt.data), tt.heap)
+			assert.True(t, errors.Is(err, tt.want), "got %v, want %v", err, tt.want)
+		})
+	}
+
+	var syntax *json.SyntaxError
+	err := json.Unmarshal([]byte(`{"d":`), NewHeap[int](2, func(a, b int) bool { return a < b }))
+	assert.True(t, errors.As(err, &syntax))
+}
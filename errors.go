@@ -0,0 +1,55 @@
+package heap
+
+import "errors"
+
+var (
+	// ErrEmpty is returned when an operation needs an element but the heap is empty.
+	ErrEmpty = errors.New("heap: empty")
+	// ErrNotFound is returned when a looked-up element is not in the heap.
+	ErrNotFound = errors.New("heap: element not found")
+	// ErrFull is returned when an element cannot be added because the heap is
+	// at its maximum size.
+	ErrFull = errors.New("heap: full")
+	// ErrInvalidBranchingFactor is returned when a heap is configured with a
+	// branching factor below 2.
+	ErrInvalidBranchingFactor = errors.New("heap: invalid branching factor")
+	// ErrBadSnapshot is returned by ReadFrom when its input is not a snapshot
+	// of a heap with the same element kind.
+	ErrBadSnapshot = errors.New("heap: bad snapshot")
+	// ErrUnsupportedKind is returned by WriteTo when the heap's elements are
+	// of a kind that snapshots cannot hold.
+	ErrUnsupportedKind = errors.New("heap: unsupported element kind")
+	// ErrCorrupt is returned by Verify when the heap's internal state is
+	// inconsistent.
+	ErrCorrupt = errors.New("heap: corrupt")
+)
+
+// TryPeek returns the extremal element without removing it, or ErrEmpty if
+// the heap is empty.
+func (h *Heap[T]) TryPeek() (T, error) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return h.Peek(), nil
+}
+
+// TryPop removes and returns the extremal element, or ErrEmpty if the heap is
+// empty.
+func (h *Heap[T]) TryPop() (T, error) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return h.Pop(), nil
+}
+
+// Lookup retrieves the first occurrence of element like Get, returning
+// ErrNotFound if it is not in the heap.
+func (h *Heap[T]) Lookup(element T) (T, error) {
+	v, ok := h.Get(element)
+	if !ok {
+		return v, ErrNotFound
+	}
+	return v, nil
+}
@@ -0,0 +1,39 @@
+package heap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorVariants(t *testing.T) {
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+
+	_, err := heap.TryPeek()
+	assert.ErrorIs(t, err, ErrEmpty)
+	_, err = heap.TryPop()
+	assert.ErrorIs(t, err, ErrEmpty)
+	_, err = heap.Lookup(1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	heap.Push(2)
+	heap.Push(1)
+
+	v, err := heap.TryPeek()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	v, err = heap.Lookup(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	v, err = heap.TryPop()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestConfigInvalidBranchingFactor(t *testing.T) {
+	_, err := New(Config[int]{D: 0, Less: func(a, b int) bool { return a < b }})
+	assert.True(t, errors.Is(err, ErrInvalidBranchingFactor), "New returned %v", err)
+}
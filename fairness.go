@@ -0,0 +1,94 @@
+package heap
+
+import (
+	"sort"
+	"time"
+)
+
+// LevelOption is a type representing configurations for a LevelQueue.
+type LevelOption[V any, P comparable] func(*LevelQueue[V, P])
+
+// ClassStats describes the waits seen by one priority class of a LevelQueue.
+type ClassStats[P comparable] struct {
+	Priority   P             // Priority level of the class
+	Queued     int           // Number of elements currently queued
+	OldestWait time.Duration // Age of the oldest queued element, zero if none
+	Waits      WaitStats     // Waits of elements popped from the class
+}
+
+// fairnessTracker records enqueue times per priority class. Because each class
+// is served in FIFO order, the oldest queued element of a class is always at
+// the front of its queue.
+type fairnessTracker[P comparable] struct {
+	clock    Clock
+	enqueued map[P]*fifo[time.Time]
+	waits    map[P]*waitRecorder
+}
+
+// WithFairnessTracking is an option that records, per priority class, how long
+// elements wait before being popped and how long the oldest queued element has
+// been waiting. If clock is nil the system clock is used.
+func WithFairnessTracking[V any, P comparable](clock Clock) LevelOption[V, P] {
+	return func(q *LevelQueue[V, P]) {
+		if clock == nil {
+			clock = SystemClock{}
+		}
+		q.fairness = &fairnessTracker[P]{
+			clock:    clock,
+			enqueued: make(map[P]*fifo[time.Time]),
+			waits:    make(map[P]*waitRecorder),
+		}
+	}
+}
+
+// Fairness returns wait statistics for every priority class that has held an
+// element, in priority order. The result marshals to JSON, so it can be
+// published directly through expvar.Func. It returns nil unless the queue was
+// created with WithFairnessTracking.
+func (q *LevelQueue[V, P]) Fairness() []ClassStats[P] {
+	f := q.fairness
+	if f == nil {
+		return nil
+	}
+	now := f.clock.Now()
+	stats := make([]ClassStats[P], 0, len(f.waits))
+	for priority, waits := range f.waits {
+		s := ClassStats[P]{Priority: priority, Waits: waits.stats()}
+		if queued := f.enqueued[priority]; queued.len() > 0 {
+			s.Queued = queued.len()
+			s.OldestWait = now.Sub(queued.front())
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return q.order.lessFunc(stats[i].Priority, stats[j].Priority) })
+	return stats
+}
+
+// Starved returns the priority classes whose oldest queued element has waited
+// longer than threshold, in priority order. It returns nil unless the queue
+// was created with WithFairnessTracking.
+func (q *LevelQueue[V, P]) Starved(threshold time.Duration) []P {
+	var starved []P
+	for _, s := range q.Fairness() {
+		if s.OldestWait > threshold {
+			starved = append(starved, s.Priority)
+		}
+	}
+	return starved
+}
+
+// pushed records that an element entered the given class.
+func (f *fairnessTracker[P]) pushed(priority P) {
+	queued, exists := f.enqueued[priority]
+	if !exists {
+		queued = &fifo[time.Time]{}
+		f.enqueued[priority] = queued
+		f.waits[priority] = &waitRecorder{}
+	}
+	queued.push(f.clock.Now())
+}
+
+// popped records that the oldest element of the given class left the queue.
+func (f *fairnessTracker[P]) popped(priority P) {
+	f.waits[priority].record(f.clock.Now().Sub(f.enqueued[priority].pop()))
+}
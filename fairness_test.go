@@ -0,0 +1,52 @@
+package heap
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelQueueFairness(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	q := NewLevelQueue[string, int](2, func(a, b int) bool { return a < b },
+		WithFairnessTracking[string, int](clock))
+
+	q.Push("bulk", 5)
+	clock.RunUntil(start.Add(10 * time.Second))
+	q.Push("urgent-1", 0)
+	q.Push("urgent-2", 0)
+	clock.RunUntil(start.Add(12 * time.Second))
+
+	q.Pop()
+	q.Pop()
+	clock.RunUntil(start.Add(60 * time.Second))
+
+	stats := q.Fairness()
+	assert.Len(t, stats, 2)
+	assert.Equal(t, 0, stats[0].Priority)
+	assert.Equal(t, 0, stats[0].Queued)
+	assert.Zero(t, stats[0].OldestWait)
+	assert.Equal(t, 2, stats[0].Waits.Count)
+	assert.Equal(t, 2*time.Second, stats[0].Waits.Max)
+
+	assert.Equal(t, 5, stats[1].Priority)
+	assert.Equal(t, 1, stats[1].Queued)
+	assert.Equal(t, 60*time.Second, stats[1].OldestWait)
+	assert.Zero(t, stats[1].Waits.Count)
+
+	assert.Equal(t, []int{5}, q.Starved(30*time.Second))
+	assert.Empty(t, q.Starved(time.Hour))
+
+	_, err := json.Marshal(stats)
+	assert.NoError(t, err)
+}
+
+func TestLevelQueueFairnessDisabled(t *testing.T) {
+	q := NewLevelQueue[string, int](2, func(a, b int) bool { return a < b })
+	q.Push("x", 1)
+	assert.Nil(t, q.Fairness())
+	assert.Nil(t, q.Starved(0))
+}
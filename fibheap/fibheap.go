@@ -0,0 +1,258 @@
+// Package fibheap provides a generic Fibonacci heap. It takes the same less
+// function as the d-ary heap package and shares the handle API of the pairing
+// package, so either can be used through heap.AddressableHeap. Push, Meld and
+// DecreaseKey run in O(1) amortized time and Pop in O(log n) amortized time,
+// which pays off at large scale for decrease-key heavy algorithms.
+package fibheap
+
+import "math/bits"
+
+// Node is a handle to an element in the heap.
+type Node[T any] struct {
+	value       T
+	parent      *Node[T]
+	child       *Node[T] // Any one of the children
+	left, right *Node[T] // Neighbors in a circular list of siblings
+	degree      int      // Number of children
+	marked      bool     // Whether the node lost a child since it became a child itself
+	owner       *owner   // Identifies the heap holding the node, nil once popped
+}
+
+// Value returns the element the handle refers to.
+func (n *Node[T]) Value() T {
+	return n.value
+}
+
+// owner identifies a heap for membership checks on handles. Melding a heap
+// into another links the absorbed heap's owner to the absorbing one, so
+// handles are re-pointed in O(1) and resolved with path compression.
+type owner struct {
+	parent *owner
+}
+
+// find returns the owner o currently resolves to.
+func (o *owner) find() *owner {
+	root := o
+	for root.parent != nil {
+		root = root.parent
+	}
+	for o != root {
+		o, o.parent = o.parent, root
+	}
+	return root
+}
+
+// Heap is a Fibonacci heap ordered by a less function.
+type Heap[T any] struct {
+	min      *Node[T] // Extremal root, nil when empty
+	size     int
+	lessFunc func(T, T) bool
+	owner    *owner
+}
+
+// NewHeap creates an empty Fibonacci heap ordered by lessFunc.
+func NewHeap[T any](lessFunc func(T, T) bool) *Heap[T] {
+	return &Heap[T]{lessFunc: lessFunc, owner: &owner{}}
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return h.size
+}
+
+// Peek returns the extremal element without removing it. If the heap is
+// empty, it returns the zero value of type T.
+func (h *Heap[T]) Peek() T {
+	if h.min == nil {
+		var zero T
+		return zero
+	}
+	return h.min.value
+}
+
+// Push adds a new element to the heap in O(1) and returns a handle to it.
+func (h *Heap[T]) Push(value T) *Node[T] {
+	n := &Node[T]{value: value, owner: h.owner}
+	n.left, n.right = n, n
+	h.addRoot(n)
+	h.size++
+	return n
+}
+
+// Pop removes and returns the extremal element in O(log n) amortized time.
+// If the heap is empty, it returns the zero value of type T.
+func (h *Heap[T]) Pop() T {
+	top := h.min
+	if top == nil {
+		var zero T
+		return zero
+	}
+
+	// Promote the children to roots.
+	for top.child != nil {
+		c := top.child
+		top.child = c.right
+		if c.right == c {
+			top.child = nil
+		}
+		splice(c)
+		c.parent, c.marked = nil, false
+		h.insertRoot(c)
+	}
+
+	if top.right == top {
+		h.min = nil
+	} else {
+		h.min = top.right
+		splice(top)
+		h.consolidate()
+	}
+	h.size--
+	top.owner, top.left, top.right = nil, nil, nil
+	return top.value
+}
+
+// Meld moves every element of other into h in O(1) and leaves other empty.
+// Both heaps must order elements the same way. Handles into other stay valid
+// and now refer to elements of h.
+func (h *Heap[T]) Meld(other *Heap[T]) {
+	if h == other || other.min == nil {
+		return
+	}
+	if h.min == nil {
+		h.min = other.min
+	} else {
+		// Concatenate the two circular root lists.
+		a, b := h.min.right, other.min.left
+		h.min.right, other.min.left = other.min, h.min
+		a.left, b.right = b, a
+		if h.lessFunc(other.min.value, h.min.value) {
+			h.min = other.min
+		}
+	}
+	h.size += other.size
+	other.owner.parent = h.owner
+	other.min, other.size, other.owner = nil, 0, &owner{}
+}
+
+// DecreaseKey replaces the element n refers to with value, which must not
+// order after it, in O(1) amortized time. It returns false without changing
+// anything if n is not in h or value orders after the current element.
+func (h *Heap[T]) DecreaseKey(n *Node[T], value T) bool {
+	if n.owner == nil || n.owner.find() != h.owner || h.lessFunc(n.value, value) {
+		return false
+	}
+	n.value = value
+	if p := n.parent; p != nil && h.lessFunc(n.value, p.value) {
+		h.cut(n)
+		h.cascadingCut(p)
+	}
+	if h.lessFunc(n.value, h.min.value) {
+		h.min = n
+	}
+	return true
+}
+
+// addRoot inserts n into the root list and updates the minimum.
+func (h *Heap[T]) addRoot(n *Node[T]) {
+	if h.min == nil {
+		n.left, n.right = n, n
+		h.min = n
+		return
+	}
+	h.insertRoot(n)
+	if h.lessFunc(n.value, h.min.value) {
+		h.min = n
+	}
+}
+
+// insertRoot inserts n into the root list next to the minimum.
+func (h *Heap[T]) insertRoot(n *Node[T]) {
+	n.left, n.right = h.min, h.min.right
+	h.min.right.left = n
+	h.min.right = n
+}
+
+// splice removes n from its circular sibling list.
+func splice[T any](n *Node[T]) {
+	n.left.right = n.right
+	n.right.left = n.left
+	n.left, n.right = n, n
+}
+
+// consolidate links roots of equal degree until every root has a distinct
+// degree, and finds the new minimum.
+func (h *Heap[T]) consolidate() {
+	// Degrees are bounded by log_φ n < 1.45 log2 n.
+	byDegree := make([]*Node[T], 2*bits.Len(uint(h.size))+2)
+
+	var roots []*Node[T]
+	for n := h.min; ; n = n.right {
+		roots = append(roots, n)
+		if n.right == h.min {
+			break
+		}
+	}
+	for _, n := range roots {
+		splice(n)
+		for byDegree[n.degree] != nil {
+			other := byDegree[n.degree]
+			byDegree[n.degree] = nil
+			if h.lessFunc(other.value, n.value) {
+				n, other = other, n
+			}
+			h.link(other, n)
+		}
+		byDegree[n.degree] = n
+	}
+
+	h.min = nil
+	for _, n := range byDegree {
+		if n != nil {
+			h.addRoot(n)
+		}
+	}
+}
+
+// link makes the root child a child of the root parent.
+func (h *Heap[T]) link(child, parent *Node[T]) {
+	child.parent, child.marked = parent, false
+	if parent.child == nil {
+		child.left, child.right = child, child
+		parent.child = child
+	} else {
+		c := parent.child
+		child.left, child.right = c, c.right
+		c.right.left = child
+		c.right = child
+	}
+	parent.degree++
+}
+
+// cut moves n from its parent's children to the root list.
+func (h *Heap[T]) cut(n *Node[T]) {
+	p := n.parent
+	if p.child == n {
+		p.child = n.right
+		if n.right == n {
+			p.child = nil
+		}
+	}
+	splice(n)
+	p.degree--
+	n.parent, n.marked = nil, false
+	h.insertRoot(n)
+}
+
+// cascadingCut cuts n from its parent if it has already lost a child, and
+// continues up the tree, which keeps the trees bushy enough for the
+// logarithmic degree bound.
+func (h *Heap[T]) cascadingCut(n *Node[T]) {
+	for p := n.parent; p != nil; n, p = p, p.parent {
+		if !n.marked {
+			n.marked = true
+			return
+		}
+		h.cut(n)
+	}
+}
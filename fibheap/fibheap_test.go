@@ -0,0 +1,74 @@
+package fibheap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	heap "github.com/ahrav/go-d-ary-heap"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ heap.AddressableHeap[int, *Node[int]] = (*Heap[int])(nil)
+
+func TestFibonacciHeap(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(1))
+	h := NewHeap[int](func(a, b int) bool { return a < b })
+	assert.Zero(t, h.Pop())
+
+	var values []int
+	var nodes []*Node[int]
+	for round := 0; round < 20; round++ {
+		for i := 0; i < 100; i++ {
+			v := rng.Intn(1000) + 1000
+			values = append(values, v)
+			nodes = append(nodes, h.Push(v))
+		}
+		// Pop a few to build trees, then decrease keys deep inside them.
+		sort.Ints(values)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, values[0], h.Pop())
+			values = values[1:]
+		}
+		for i := 0; i < 30; i++ {
+			k := rng.Intn(len(nodes))
+			n := nodes[k]
+			v := n.Value()
+			if n.owner == nil {
+				assert.False(t, h.DecreaseKey(n, v-1))
+				continue
+			}
+			next := v - rng.Intn(2000)
+			idx := sort.SearchInts(values, v)
+			assert.True(t, h.DecreaseKey(n, next))
+			values[idx] = next
+			sort.Ints(values)
+		}
+		assert.Equal(t, len(values), h.Len())
+	}
+	for _, want := range values {
+		assert.Equal(t, want, h.Pop())
+	}
+	assert.Zero(t, h.Len())
+}
+
+func TestFibonacciHeapMeld(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	a, b := NewHeap(less), NewHeap(less)
+	a.Push(5)
+	nb := b.Push(7)
+	b.Push(2)
+	a.Meld(b)
+	a.Meld(a)
+	assert.Zero(t, b.Len())
+	assert.False(t, b.DecreaseKey(nb, 1))
+	assert.True(t, a.DecreaseKey(nb, 1))
+	assert.False(t, a.DecreaseKey(nb, 3), "increase accepted")
+	for _, want := range []int{1, 2, 5} {
+		assert.Equal(t, want, a.Pop())
+	}
+}
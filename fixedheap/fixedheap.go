@@ -0,0 +1,96 @@
+// Package fixedheap provides a d-ary heap for embedded and TinyGo targets. It
+// stores elements in a caller-supplied array, never allocates after
+// construction, keeps no index map and imports nothing, not even the standard
+// library, so it also fits allocation-restricted firmware schedulers.
+//
+// It trades the features of the main package (Contains, Remove, options) for
+// that footprint: only Push, Pop and Peek are supported.
+package fixedheap
+
+// Heap is a fixed-capacity d-ary heap backed by a caller-supplied slice.
+type Heap[T any] struct {
+	data []T // Backing array; len(data) is the capacity
+	n    int // Number of elements in the heap
+	d    int // Branching factor
+	less func(a, b T) bool
+}
+
+// New creates an empty heap with branching factor d that stores its elements
+// in buf and orders them by less. The capacity of the heap is len(buf). The
+// heap owns buf until it is no longer used.
+func New[T any](d int, buf []T, less func(a, b T) bool) *Heap[T] {
+	if d < 2 {
+		d = 2
+	}
+	return &Heap[T]{data: buf, d: d, less: less}
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int { return h.n }
+
+// Cap returns the maximum number of elements the heap can hold.
+func (h *Heap[T]) Cap() int { return len(h.data) }
+
+// Push adds value to the heap. It returns false without modifying the heap if
+// the heap is full.
+func (h *Heap[T]) Push(value T) bool {
+	if h.n == len(h.data) {
+		return false
+	}
+	i := h.n
+	h.n++
+	for i > 0 {
+		p := (i - 1) / h.d
+		if !h.less(value, h.data[p]) {
+			break
+		}
+		h.data[i] = h.data[p]
+		i = p
+	}
+	h.data[i] = value
+	return true
+}
+
+// Peek returns the extremal element without removing it. If the heap is
+// empty, it returns the zero value of type T and false.
+func (h *Heap[T]) Peek() (T, bool) {
+	if h.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.data[0], true
+}
+
+// Pop removes and returns the extremal element. If the heap is empty, it
+// returns the zero value of type T and false.
+func (h *Heap[T]) Pop() (T, bool) {
+	var zero T
+	if h.n == 0 {
+		return zero, false
+	}
+	top := h.data[0]
+	h.n--
+	last := h.data[h.n]
+	h.data[h.n] = zero
+
+	// Move the hole at the root down to where the last element belongs.
+	i := 0
+	for {
+		best := -1
+		first := h.d*i + 1
+		for c := first; c < first+h.d && c < h.n; c++ {
+			if best < 0 || h.less(h.data[c], h.data[best]) {
+				best = c
+			}
+		}
+		if best < 0 || !h.less(h.data[best], last) {
+			break
+		}
+		h.data[i] = h.data[best]
+		i = best
+	}
+	if h.n > 0 {
+		h.data[i] = last
+	}
+	return top, true
+}
@@ -0,0 +1,52 @@
+package fixedheap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedHeap(t *testing.T) {
+	for _, d := range []int{2, 3, 4} {
+		var buf [64]int
+		h := New(d, buf[:], func(a, b int) bool { return a < b })
+		assert.Equal(t, 64, h.Cap())
+
+		rng := rand.New(rand.NewSource(int64(d)))
+		var values []int
+		for i := 0; i < 64; i++ {
+			v := rng.Intn(50)
+			values = append(values, v)
+			assert.True(t, h.Push(v))
+		}
+		assert.False(t, h.Push(0), "Push succeeded on a full heap")
+
+		sort.Ints(values)
+		top, ok := h.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, values[0], top)
+		for _, want := range values {
+			got, ok := h.Pop()
+			assert.True(t, ok)
+			assert.Equal(t, want, got, "d=%d", d)
+		}
+		_, ok = h.Pop()
+		assert.False(t, ok)
+		assert.Zero(t, h.Len())
+	}
+}
+
+func TestFixedHeapDoesNotAllocate(t *testing.T) {
+	buf := make([]int, 128)
+	h := New(4, buf, func(a, b int) bool { return a > b })
+	for i := 0; i < 100; i++ {
+		h.Push(i)
+	}
+	allocs := testing.AllocsPerRun(1000, func() {
+		v, _ := h.Pop()
+		h.Push(v - 1)
+	})
+	assert.Zero(t, allocs)
+}
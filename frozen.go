@@ -0,0 +1,121 @@
+package heap
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// FrozenHeap is an immutable snapshot of a heap's elements stored as a compact
+// array sorted in heap order. It serves sorted iteration, binary search and
+// rank queries, which suits a queue that moves from a build phase to a
+// read-only serving phase.
+//
+// A FrozenHeap is never modified after it is created, so it may be shared by
+// any number of goroutines without synchronization. A writer can publish
+// successive snapshots through an atomic.Pointer and readers pick up the
+// latest one without blocking it.
+type FrozenHeap[T comparable] struct {
+	sorted []T
+	less   func(T, T) bool
+}
+
+// Freeze consumes the heap and returns its elements as a FrozenHeap. The
+// elements are sorted in place in O(n log n) without further allocation, and
+// the heap is left empty with no backing storage.
+func (h *Heap[T]) Freeze() *FrozenHeap[T] {
+	h.ensureHeap()
+	sorted := h.data[:h.heapSize]
+	sortHeapSlice(sorted, h.d, h.lessFunc)
+
+	h.data = nil
+	h.reset()
+	return &FrozenHeap[T]{sorted: sorted, less: h.lessFunc}
+}
+
+// sortHeapSlice sorts s, which must already be a d-ary heap ordered by less,
+// into heap order in place.
+func sortHeapSlice[T any](s []T, d int, less func(T, T) bool) {
+	for n := len(s); n > 1; n-- {
+		popSlice(s, n, d, less)
+	}
+	slices.Reverse(s)
+}
+
+// Snapshot returns a FrozenHeap holding a sorted copy of the heap's elements,
+// leaving the heap unchanged. It costs O(n log n) time and O(n) space.
+func (h *Heap[T]) Snapshot() *FrozenHeap[T] {
+	return &FrozenHeap[T]{sorted: h.ToSortedSlice(), less: h.lessFunc}
+}
+
+// ToSortedSlice returns a new slice holding the heap's elements in priority
+// order, leaving the heap unchanged. It heap-sorts a copy in O(n log n).
+// Expired elements are left out.
+func (h *Heap[T]) ToSortedSlice() []T {
+	live := h.live()
+	sorted := make([]T, len(live))
+	copy(sorted, live)
+	if len(live) < h.heapSize {
+		heapifySlice(sorted, h.d, h.lessFunc) // Expired elements left gaps
+	}
+	sortHeapSlice(sorted, h.d, h.lessFunc)
+	return sorted
+}
+
+// Thaw creates a live heap with branching factor d holding the elements of f,
+// ordered by the comparator of the heap f was frozen from. A sorted array is
+// already a valid heap for any branching factor, so this is a single O(n) copy
+// with no sifting. f is left unchanged.
+func (f *FrozenHeap[T]) Thaw(d int, options ...Option[T]) *Heap[T] {
+	h := NewHeap(d, f.less, options...)
+	h.load(f.sorted)
+	h.trim()
+	return h
+}
+
+// Len returns the number of elements.
+func (f *FrozenHeap[T]) Len() int {
+	return len(f.sorted)
+}
+
+// At returns the element of rank i, where rank 0 is the element the heap
+// would have popped first. It panics if i is out of range.
+func (f *FrozenHeap[T]) At(i int) T {
+	return f.sorted[i]
+}
+
+// All returns an iterator over the elements in heap order.
+func (f *FrozenHeap[T]) All() iter.Seq[T] {
+	return slices.Values(f.sorted)
+}
+
+// Search reports whether x is one of the elements and returns its rank if so.
+// Otherwise it returns the rank of the first element that does not order
+// before x, which is len if every element orders before x. Elements that tie
+// with x under the comparator are compared with x one by one, so distinct
+// values that order equally are told apart.
+func (f *FrozenHeap[T]) Search(x T) (int, bool) {
+	lo := f.CountLess(x)
+	for i := lo; i < len(f.sorted) && !f.less(x, f.sorted[i]); i++ {
+		if f.sorted[i] == x {
+			return i, true
+		}
+	}
+	return lo, false
+}
+
+// Contains reports whether x is one of the elements.
+func (f *FrozenHeap[T]) Contains(x T) bool {
+	_, found := f.Search(x)
+	return found
+}
+
+// CountLess returns the number of elements that order before x.
+func (f *FrozenHeap[T]) CountLess(x T) int {
+	return sort.Search(len(f.sorted), func(i int) bool { return !f.less(f.sorted[i], x) })
+}
+
+// CountAtMost returns the number of elements that do not order after x.
+func (f *FrozenHeap[T]) CountAtMost(x T) int {
+	return sort.Search(len(f.sorted), func(i int) bool { return f.less(x, f.sorted[i]) })
+}
@@ -0,0 +1,117 @@
+package heap
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreeze(t *testing.T) {
+	heap := NewHeap[int](3, func(a, b int) bool { return a > b })
+	for _, v := range []int{4, 8, 1, 8, 5, 2} {
+		heap.Push(v)
+	}
+
+	frozen := heap.Freeze()
+	assert.Zero(t, heap.heapSize, "Freeze did not consume the heap")
+	assert.False(t, heap.Contains(4))
+
+	assert.Equal(t, 6, frozen.Len())
+	assert.Equal(t, []int{8, 8, 5, 4, 2, 1}, slices.Collect(frozen.All()))
+	assert.Equal(t, 5, frozen.At(2))
+
+	rank, found := frozen.Search(4)
+	assert.Equal(t, 3, rank)
+	assert.True(t, found)
+	rank, found = frozen.Search(3)
+	assert.Equal(t, 4, rank)
+	assert.False(t, found)
+
+	assert.True(t, frozen.Contains(1))
+	assert.False(t, frozen.Contains(7))
+	assert.Equal(t, 0, frozen.CountLess(8))
+	assert.Equal(t, 2, frozen.CountAtMost(8))
+	assert.Equal(t, 6, frozen.CountLess(0))
+
+	// The heap can be reused after freezing.
+	heap.Push(3)
+	assert.Equal(t, 3, heap.Pop())
+}
+
+func TestFrozenHeapTiedKeys(t *testing.T) {
+	t.Parallel()
+
+	type task struct {
+		priority int
+		name     string
+	}
+	heap := NewHeap[task](2, func(a, b task) bool { return a.priority < b.priority })
+	for _, v := range []task{{2, "x"}, {1, "a"}, {1, "b"}, {1, "c"}, {0, "z"}} {
+		heap.Push(v)
+	}
+	assert.True(t, heap.Contains(task{1, "b"}))
+
+	frozen := heap.Freeze()
+	for _, v := range []task{{1, "a"}, {1, "b"}, {1, "c"}} {
+		rank, found := frozen.Search(v)
+		assert.True(t, found, "Search(%v)", v)
+		assert.Equal(t, v, frozen.At(rank))
+		assert.True(t, frozen.Contains(v))
+	}
+	rank, found := frozen.Search(task{1, "d"})
+	assert.False(t, found)
+	assert.Equal(t, 1, rank, "a missing value ranks before its ties")
+	assert.False(t, frozen.Contains(task{3, "x"}))
+}
+
+func TestFrozenHeapSharedSnapshots(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	live := NewHeap[int](2, less)
+
+	var published atomic.Pointer[FrozenHeap[int]]
+	published.Store(live.Snapshot())
+
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				snap := published.Load()
+				assert.True(t, slices.IsSorted(slices.Collect(snap.All())))
+			}
+		}()
+	}
+	for i := 100; i > 0; i-- {
+		live.Push(i)
+		published.Store(live.Snapshot())
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, live.heapSize, "Snapshot consumed the heap")
+	snap := published.Load()
+	assert.Equal(t, 100, snap.Len())
+
+	// A standby rebuilds a live heap from the published snapshot.
+	standby := snap.Thaw(4)
+	assert.Equal(t, 100, snap.Len(), "Thaw modified the snapshot")
+	assert.True(t, standby.Contains(42))
+	for want := 1; want <= 100; want++ {
+		assert.Equal(t, want, standby.Pop())
+	}
+}
+
+func TestHeapToSortedSlice(t *testing.T) {
+	heap := NewHeap[string](3, func(a, b string) bool { return a > b })
+	assert.Empty(t, heap.ToSortedSlice())
+	for _, v := range []string{"pear", "apple", "fig", "kiwi", "apple"} {
+		heap.Push(v)
+	}
+
+	assert.Equal(t, []string{"pear", "kiwi", "fig", "apple", "apple"}, heap.ToSortedSlice())
+	assert.Equal(t, 5, heap.Len(), "ToSortedSlice modified the heap")
+	assert.Equal(t, "pear", heap.Pop())
+}
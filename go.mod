@@ -1,11 +1,8 @@
 module github.com/ahrav/go-d-ary-heap
 
-go 1.21.1
+go 1.23
 
-require (
-	github.com/stretchr/testify v1.9.0
-	golang.org/x/exp v0.0.0-20240222234643-814bf88cf225
-)
+require github.com/stretchr/testify v1.9.0
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
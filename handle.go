@@ -0,0 +1,142 @@
+package heap
+
+import "fmt"
+
+// Item is a handle to an element pushed with PushHandle. It follows the
+// element as it moves through the heap, so the element can be removed or
+// updated in O(log n) even when other elements compare equal to it, without
+// consulting the value-keyed index.
+type Item[T comparable] struct {
+	heap  *Heap[T] // Heap holding the element, nil once it has left the heap
+	index int      // Position of the element in the heap
+}
+
+// Value returns the element the handle refers to. If the element is no longer
+// in the heap, it returns the zero value of type T and false.
+func (it *Item[T]) Value() (T, bool) {
+	if it.heap == nil {
+		var zero T
+		return zero, false
+	}
+	return it.heap.data[it.index], true
+}
+
+// PushHandle adds a new element to the heap and returns a handle to it. If the
+// heap is at its maximum size, it evicts like Push; when value itself is
+// evicted, the returned handle no longer refers to an element.
+func (h *Heap[T]) PushHandle(value T) *Item[T] {
+	if h.full() {
+		h.ensureTop() // Expired elements may be holding the room
+	}
+	if h.full() {
+		if !h.lessFunc(h.data[0], value) {
+			if h.hooks != nil {
+				h.hooks.evicted(value)
+			}
+			return &Item[T]{}
+		}
+		evicted := h.removeAt(0)
+		if h.hooks != nil {
+			h.hooks.evicted(evicted)
+		}
+	}
+	if h.items == nil {
+		h.items = make([]*Item[T], h.heapSize, cap(h.data))
+	}
+	it := &Item[T]{heap: h, index: h.heapSize}
+	h.push(value, it)
+	return it
+}
+
+// RemoveHandle removes the element it refers to from the heap. It returns
+// false if the element has already left the heap.
+func (h *Heap[T]) RemoveHandle(it *Item[T]) bool {
+	h.mergeStaged()
+	h.purge() // The element may have been lazily removed
+	if it.heap != h {
+		return false
+	}
+	h.removeAt(it.index)
+	return true
+}
+
+// UpdateHandle replaces the element it refers to with value and re-sifts it
+// in place. It returns false if the element has already left the heap.
+func (h *Heap[T]) UpdateHandle(it *Item[T], value T) bool {
+	h.mergeStaged()
+	h.purge() // The element may have been lazily removed
+	if it.heap != h {
+		return false
+	}
+	h.updateAt(it.index, value)
+	return true
+}
+
+// UpdateHandles replaces the element each of items refers to with the value
+// at the same position in values, like UpdateBatch but targeting exact
+// elements even among duplicates. Handles whose element has already left the
+// heap are skipped. It returns the number of elements updated and panics if
+// items and values differ in length.
+func (h *Heap[T]) UpdateHandles(items []*Item[T], values []T) int {
+	if len(items) != len(values) {
+		panic(fmt.Sprintf("heap: %d handles but %d values", len(items), len(values)))
+	}
+	h.mergeStaged()
+	h.purge() // Elements may have been lazily removed
+	return h.updateBatch(len(items), func(k int) (int, T, bool) {
+		if it := items[k]; it.heap == h {
+			return it.index, values[k], true
+		}
+		var zero T
+		return 0, zero, false
+	})
+}
+
+// trackSlot records it, which may be nil, as the handle of the element
+// entering the heap at index i. It is called for every element entering the
+// heap once handles are in use.
+func (h *Heap[T]) trackSlot(i int, it *Item[T]) {
+	if i == len(h.items) {
+		h.items = append(h.items, it)
+		return
+	}
+	h.items[i] = it
+}
+
+// swapItems exchanges the handles at indices i and j.
+func (h *Heap[T]) swapItems(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	if it := h.items[i]; it != nil {
+		it.index = i
+	}
+	if it := h.items[j]; it != nil {
+		it.index = j
+	}
+}
+
+// releaseSlot invalidates the handle of the element leaving the heap from
+// index i.
+func (h *Heap[T]) releaseSlot(i int) {
+	if it := h.items[i]; it != nil {
+		it.heap = nil
+		h.items[i] = nil
+	}
+}
+
+// releaseAll invalidates every handle, for operations that empty the heap.
+func (h *Heap[T]) releaseAll() {
+	for i := range h.items[:min(h.heapSize, len(h.items))] {
+		h.releaseSlot(i)
+	}
+	h.items = h.items[:0]
+}
+
+// adoptItems points every handle at h, after the contents of another heap
+// have moved into it.
+func (h *Heap[T]) adoptItems() {
+	for _, it := range h.items {
+		if it != nil {
+			it.heap = h
+		}
+	}
+}
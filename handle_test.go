@@ -0,0 +1,142 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandles(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b })
+	heap.Push(4)
+	first := heap.PushHandle(5)
+	second := heap.PushHandle(5) // Duplicate of first
+	heap.Push(1)
+	third := heap.PushHandle(7)
+
+	v, ok := second.Value()
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+
+	// Removing by handle removes exactly that element, not its duplicate.
+	assert.True(t, heap.RemoveHandle(second))
+	_, ok = second.Value()
+	assert.False(t, ok)
+	assert.False(t, heap.RemoveHandle(second), "removed twice")
+	assert.True(t, heap.Contains(5))
+
+	assert.True(t, heap.UpdateHandle(third, 0))
+	assert.Equal(t, 0, heap.Peek())
+	assert.True(t, heap.UpdateHandle(first, 9))
+
+	for _, want := range []int{0, 1, 4, 9} {
+		assert.Equal(t, want, heap.Pop())
+	}
+	_, ok = third.Value()
+	assert.False(t, ok, "handle still valid after its element was popped")
+	assert.False(t, heap.UpdateHandle(first, 3))
+	assert.Empty(t, heap.index)
+}
+
+func TestHandlesFollowElements(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a > b }, WithStagingBuffer[int](4))
+	items := make([]*Item[int], 50)
+	for i := range items {
+		items[i] = heap.PushHandle(i)
+	}
+	for i := 0; i < 50; i += 2 {
+		assert.True(t, heap.UpdateHandle(items[i], 100+i))
+	}
+	for i, it := range items {
+		v, ok := it.Value()
+		assert.True(t, ok)
+		if i%2 == 0 {
+			assert.Equal(t, 100+i, v)
+		} else {
+			assert.Equal(t, i, v)
+		}
+	}
+
+	other := NewHeap[int](2, func(a, b int) bool { return a > b })
+	heap.Swap(other)
+	assert.True(t, other.RemoveHandle(items[48]))
+	assert.False(t, heap.RemoveHandle(items[46]), "handle still bound to the old heap")
+	assert.Equal(t, 146, other.Pop())
+
+	other.CopyFrom(NewHeap[int](2, func(a, b int) bool { return a > b }))
+	_, ok := items[0].Value()
+	assert.False(t, ok, "handle survived reset")
+}
+
+func TestHandlesAfterLazyRemove(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	heap.PushHandle(1)
+	heap.PushHandle(2)
+	third := heap.PushHandle(3)
+
+	assert.True(t, heap.LazyRemove(3))
+	assert.False(t, heap.RemoveHandle(third), "element already lazily removed")
+	assert.False(t, heap.UpdateHandle(third, 0))
+	assert.Equal(t, 2, heap.Len())
+	assert.NoError(t, heap.Verify())
+
+	heap.Push(3)
+	assert.True(t, heap.Contains(3))
+	assert.Equal(t, []int{1, 2, 3}, heap.PopN(3))
+}
+
+func TestUpdateHandles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		n    int
+	}{
+		{name: "small batch sifts", n: 200},
+		{name: "large batch rebuilds", n: 6},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			heap := NewHeap[int](3, func(a, b int) bool { return a < b },
+				WithAggregates(func(v int) float64 { return float64(v) }))
+			items := make([]*Item[int], tt.n)
+			for i := range items {
+				items[i] = heap.PushHandle(5) // All equal, so only handles tell them apart
+			}
+			gone := heap.PushHandle(7)
+			assert.True(t, heap.RemoveHandle(gone))
+
+			updated := heap.UpdateHandles([]*Item[int]{items[1], gone, items[3]}, []int{1, 0, 9})
+			assert.Equal(t, 2, updated, "handle of a removed element applied")
+			for i, it := range items {
+				v, _ := it.Value()
+				switch i {
+				case 1:
+					assert.Equal(t, 1, v)
+				case 3:
+					assert.Equal(t, 9, v)
+				default:
+					assert.Equal(t, 5, v)
+				}
+			}
+			assert.Equal(t, float64(5*(tt.n-2)+10), heap.Aggregates().Sum)
+			assert.NoError(t, heap.Verify())
+			assert.Equal(t, 1, heap.Pop())
+		})
+	}
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	assert.PanicsWithValue(t, "heap: 1 handles but 0 values", func() {
+		heap.UpdateHandles([]*Item[int]{heap.PushHandle(1)}, nil)
+	})
+}
@@ -1,8 +1,9 @@
 // Package heap provides operations for a generic d-ary heap. Unlike the standard
 // library's heap package, which requires types to implement the heap.Interface,
 // this package offers a concrete implementation of a d-ary heap that works with
-// any ordered type, as defined by the constraints.Ordered constraint from
-// golang.org/x/exp/constraints.
+// any comparable type, ordered by a caller-supplied less function. Elements
+// only need to be comparable so the heap can index their positions, so structs
+// ordered by one of their fields work as well as numbers and strings.
 //
 // A d-ary heap is a variation of the binary heap where each node can have up to
 // d children instead of just two. This allows for a more shallow heap for the
@@ -14,18 +15,22 @@
 // The Heap struct in this package encapsulates the d-ary heap's state, including
 // the heap's elements, its branching factor (d), and a custom less function to
 // determine the order of elements. This implementation allows for a flexible and
-// generic heap that can handle any ordered type without requiring additional
+// generic heap that can handle any comparable type without requiring additional
 // methods on the type itself.
 //
 // Basic operations provided include:
 // - NewHeap: to initialize a new d-ary heap with a specified branching factor and ordering function.
+// - NewMin and NewMax: to initialize a heap of cmp.Ordered values without writing a comparator.
+// - NewHeapFromSlice: to build a heap from existing elements in O(n).
 // - Push: to add new elements to the heap while maintaining the heap property.
 // - Pop: to remove and return the extremal element from the heap.
+// - PushPop and Replace: to push and pop in a single sift.
 // - Peek: to return the extremal element without removing it.
+// - Len, Cap, IsEmpty and Clear: to inspect the size of the heap and empty it.
 // - Contains: to check if the heap contains a given element.
 // - Get: to retrieve the first occurrence of an element from the heap.
-// - Remove: to remove an element from the heap and then restore the heap property. (TODO)
-// - Update: to change an element's value and then restore the heap property. (TODO)
+// - Remove: to remove an element from the heap and then restore the heap property.
+// - Update: to change an element's value and then restore the heap property.
 //
 // This package is designed for use cases where a priority queue or any other
 // application requires a dynamically ordered set of elements and can benefit
@@ -35,78 +40,314 @@
 package heap
 
 import (
-	"golang.org/x/exp/constraints"
+	"cmp"
+	"math/bits"
 )
 
 // Heap struct represents a generic d-ary heap.
-type Heap[T constraints.Ordered] struct {
-	data     []T             // Underlying array to store the heap elements
-	d        int             // Branching factor (number of children per node)
-	heapSize int             // Current size of the heap
-	lessFunc func(T, T) bool // Function to determine order
-	index    map[T][]int     // Hash map to store the indices of each element in the heap
+type Heap[T comparable] struct {
+	data      []T                     // Underlying array to store the heap elements
+	d         int                     // Branching factor (number of children per node)
+	shift     int                     // log2(d) when d is a power of two, zero otherwise
+	minChild  func(*Heap[T], int) int // Picks among a node and its children, nil for the general loop
+	heapSize  int                     // Current size of the heap
+	lessFunc  func(T, T) bool         // Function to determine order
+	index     map[T][]int             // Hash map to store the indices of each element in the heap
+	wait      *waitTracker[T]         // Enqueue times of each element, nil unless tracking waits
+	agg       *aggregator[T]          // Running aggregates, nil unless maintaining aggregates
+	strategy  SiftStrategy            // Algorithm used by down
+	path      []int                   // Scratch space for bottom-up sift-down
+	lazy      bool                    // Whether Push defers restoring the heap property
+	dirty     bool                    // Whether the heap property needs restoring
+	stageSize int                     // Capacity of the staging buffer, zero when pushes are not staged
+	staged    int                     // Number of elements at the end of the heap not yet sifted up
+	adaptive  *adaptiveState          // Branching factor policy, nil unless adaptive
+	profile   *siftProfiler           // Sift depth recorder, nil unless profiling
+	stats     *statsCollector         // Operation counters, nil unless collecting stats
+	hooks     *mutationHooks[T]       // Mutation callbacks, nil unless any are registered
+	ttl       *ttlTracker             // Expiry of each element, nil until PushWithTTL or WithTTLClock is used
+	spare     [][]int                 // Emptied index slices kept for reuse by addIndex
+	hash      func(T) uint64          // Hash keying the index, nil to key it by value
+	hashed    map[uint64][]int        // Indices of each element keyed by hash when hash is set
+	keyed     keyIndex[T]             // Indices of each element keyed by identity, nil unless WithIdentity
+	noIndex   bool                    // Whether positions go unrecorded and lookups scan the heap
+	noZero    bool                    // Whether vacated slots keep their removed elements
+	tombs     map[T]int               // Occurrences of each element deleted by LazyRemove
+	dead      int                     // Total occurrences deleted by LazyRemove and not yet removed
+
+	highWater int               // Largest size reached
+	occupancy *occupancyTracker // Decaying average size, nil unless tracking occupancy
+	shrink    *shrinkPolicy     // Automatic compaction policy, nil unless shrinking
+	maxSize   int               // Maximum number of elements, zero for no limit
+	oplog     func(Op[T])       // Receives every mutation, nil unless logging
+	items     []*Item[T]        // Handle of each element, parallel to data, nil until PushHandle is used
+	opSeq     uint64            // Sequence number of the last logged mutation
 }
 
 // Option is a type representing configurations for the heap
-type Option[T constraints.Ordered] func(*Heap[T])
+type Option[T comparable] func(*Heap[T])
 
 // WithCapacity is an option that sets the initial capacity of the heap
-func WithCapacity[T constraints.Ordered](capacity int) Option[T] {
+func WithCapacity[T comparable](capacity int) Option[T] {
 	return func(h *Heap[T]) {
 		h.data = make([]T, capacity)
-		h.index = make(map[T][]int, capacity)
+		h.newIndex(capacity)
+	}
+}
+
+// WithBackingSlice is an option that stores the heap's elements in buf, which
+// may be preallocated from an arena or other caller-managed memory. The heap
+// starts empty, discarding the contents of buf, and uses buf's capacity
+// without reallocating; it only moves to a new array if it outgrows it.
+func WithBackingSlice[T comparable](buf []T) Option[T] {
+	return func(h *Heap[T]) {
+		h.data = buf[:0]
+		h.newIndex(cap(buf))
 	}
 }
 
 // NewHeap creates a new d-ary heap with the specified branching factor.
-func NewHeap[T constraints.Ordered](d int, lessFunc func(T, T) bool, options ...Option[T]) *Heap[T] {
+func NewHeap[T comparable](d int, lessFunc func(T, T) bool, options ...Option[T]) *Heap[T] {
 	const defaultCapacity = 16
 	heap := &Heap[T]{
-		d:        d,
 		data:     make([]T, 0, defaultCapacity),
 		heapSize: 0,
 		lessFunc: lessFunc,
 		index:    make(map[T][]int, defaultCapacity),
 	}
+	heap.setBranching(d)
 
 	for _, option := range options {
 		option(heap)
 	}
+	if heap.shrink != nil {
+		heap.shrink.floor = cap(heap.data)
+	}
+
+	return heap
+}
+
+// NewMin creates a d-ary min-heap of ordered values, compared with cmp.Less.
+func NewMin[T cmp.Ordered](d int, options ...Option[T]) *Heap[T] {
+	return NewHeap(d, cmp.Less[T], options...)
+}
+
+// NewMax creates a d-ary max-heap of ordered values, compared with cmp.Less.
+func NewMax[T cmp.Ordered](d int, options ...Option[T]) *Heap[T] {
+	return NewHeap(d, func(a, b T) bool { return cmp.Less(b, a) }, options...)
+}
+
+// setBranching sets the branching factor to d and selects shift-based index
+// math when d is a power of two.
+func (h *Heap[T]) setBranching(d int) {
+	h.d = d
+	h.shift = 0
+	if d > 1 && d&(d-1) == 0 {
+		h.shift = bits.TrailingZeros(uint(d))
+	}
+	switch d {
+	case 2:
+		h.minChild = (*Heap[T]).minChild2
+	case 3:
+		h.minChild = (*Heap[T]).minChild3
+	case 4:
+		h.minChild = (*Heap[T]).minChild4
+	default:
+		h.minChild = nil
+	}
+}
 
+// NewHeapFromSlice creates a d-ary heap holding items. It takes ownership of
+// items, reordering them in place, and builds the heap bottom-up with Floyd's
+// method and the index in a single pass, which is O(n) rather than the
+// O(n log_d n) of pushing them one by one.
+func NewHeapFromSlice[T comparable](d int, lessFunc func(T, T) bool, items []T, options ...Option[T]) *Heap[T] {
+	heap := NewHeap(d, lessFunc, append(options[:len(options):len(options)], WithBackingSlice(items))...)
+	heap.load(items)
+	heap.heapify()
 	return heap
 }
 
 // parent returns the index of the parent node for a given index.
 func (h *Heap[T]) parent(i int) int {
+	if h.shift > 0 {
+		return (i - 1) >> h.shift
+	}
 	return (i - 1) / h.d
 }
 
 // child returns the index of the k-th child of a given index.
 func (h *Heap[T]) child(i, k int) int {
+	if h.shift > 0 {
+		return i<<h.shift + k
+	}
 	return h.d*i + k
 }
 
 // swap swaps the elements at indices i and j and updates the index hash map.
 func (h *Heap[T]) swap(i, j int) {
+	if i == j {
+		return
+	}
 	h.data[i], h.data[j] = h.data[j], h.data[i]
-	h.updateIndex(h.data[i], i)
-	h.updateIndex(h.data[j], j)
+	if h.stats != nil {
+		h.stats.stats.Swaps++
+	}
+	if h.wait != nil {
+		h.wait.enqueued[i], h.wait.enqueued[j] = h.wait.enqueued[j], h.wait.enqueued[i]
+	}
+	if h.ttl != nil {
+		h.ttl.expires[i], h.ttl.expires[j] = h.ttl.expires[j], h.ttl.expires[i]
+	}
+	if h.items != nil {
+		h.swapItems(i, j)
+	}
+	if h.data[i] == h.data[j] {
+		return // Equal elements share an index entry, nothing to move.
+	}
+	h.moveIndex(h.data[i], j, i)
+	h.moveIndex(h.data[j], i, j)
 }
 
-// updateIndex updates the index hash map for the given element and index.
-func (h *Heap[T]) updateIndex(element T, index int) {
-	indices := h.index[element]
-	// If the element has an index that is the same as the given index, we don't need to update.
-	for _, idx := range indices {
-		if idx == index {
-			break
+// addIndex records that element is stored at index i.
+func (h *Heap[T]) addIndex(element T, i int) {
+	if h.noIndex {
+		return
+	}
+	if h.keyed != nil {
+		h.keyed.add(element, i)
+		return
+	}
+	if h.hash != nil {
+		indexAdd(h.hashed, h.hash(element), i, &h.spare)
+		return
+	}
+	indexAdd(h.index, element, i, &h.spare)
+}
+
+// moveIndex updates the recorded position of element from index from to index to.
+func (h *Heap[T]) moveIndex(element T, from, to int) {
+	if h.noIndex {
+		return
+	}
+	if h.keyed != nil {
+		h.keyed.move(element, from, to)
+		return
+	}
+	if h.hash != nil {
+		indexMove(h.hashed, h.hash(element), from, to)
+		return
+	}
+	indexMove(h.index, element, from, to)
+}
+
+// removeIndex forgets that element is stored at index i.
+func (h *Heap[T]) removeIndex(element T, i int) {
+	if h.noIndex {
+		return
+	}
+	if h.keyed != nil {
+		h.keyed.remove(element, i)
+		return
+	}
+	if h.hash != nil {
+		indexRemove(h.hashed, h.hash(element), i, &h.spare)
+		return
+	}
+	indexRemove(h.index, element, i, &h.spare)
+}
+
+// find returns the index of the first recorded occurrence of element.
+func (h *Heap[T]) find(element T) (int, bool) {
+	if h.dead > 0 {
+		h.purge()
+	}
+	if h.noIndex {
+		for i, v := range h.data[:h.heapSize] {
+			if v == element {
+				return i, true
+			}
 		}
+		return 0, false
+	}
+	if h.keyed != nil {
+		return h.keyed.find(element)
+	}
+	if h.hash != nil {
+		for _, i := range h.hashed[h.hash(element)] {
+			if h.data[i] == element {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+	indices, exists := h.index[element]
+	if !exists || len(indices) == 0 {
+		return 0, false
+	}
+	return indices[0], true
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return h.heapSize - h.dead
+}
+
+// IsEmpty reports whether the heap holds no elements. When elements were
+// pushed with PushWithTTL it first discards expired elements from the root,
+// so a heap whose remaining elements have all expired is empty.
+func (h *Heap[T]) IsEmpty() bool {
+	if h.ttl != nil {
+		h.ensureTop()
+	}
+	return h.Len() == 0
+}
+
+// Clear removes every element from the heap, keeping its backing array and
+// index map allocated for reuse.
+func (h *Heap[T]) Clear() {
+	h.reset()
+}
+
+// Reset returns the heap to the state of a newly created heap so it can be
+// reused, for example by putting it back in a sync.Pool between requests.
+// Like Clear it removes every element and invalidates every handle; it also
+// discards what the heap has recorded about its past use: the counters of
+// Stats, wait times, the sift profile, the average occupancy, the high-water
+// mark and the progress of adaptive branching and automatic shrinking.
+//
+// Reset keeps the capacity of the backing array, index and per-element
+// metadata, so a pooled heap does not allocate again until it outgrows its
+// largest previous use. It also keeps the comparator, the current branching
+// factor and every option, including callbacks and clocks. An operation log
+// registered with WithOpLog receives an OpReset and keeps its sequence.
+func (h *Heap[T]) Reset() {
+	h.reset()
+	h.highWater = 0
+	if h.stats != nil {
+		h.stats.stats, h.stats.maxSize = Stats{}, 0
+	}
+	if h.wait != nil {
+		h.wait.waits = waitRecorder{samples: h.wait.waits.samples[:0]}
+	}
+	if h.profile != nil {
+		h.profile.profile.Up = h.profile.profile.Up[:0]
+		h.profile.profile.Down = h.profile.profile.Down[:0]
+	}
+	if h.occupancy != nil {
+		h.occupancy.average, h.occupancy.primed = 0, false
+	}
+	if h.adaptive != nil {
+		h.adaptive.pushes, h.adaptive.pops = 0, 0
+	}
+	if h.shrink != nil {
+		h.shrink.low = 0
 	}
-	h.index[element][0] = index
 }
 
 // Peek returns the minimum element from the heap without removing it.
 func (h *Heap[T]) Peek() T {
+	h.ensureTop()
 	if h.heapSize == 0 {
 		var zero T
 		return zero
@@ -116,7 +357,7 @@ func (h *Heap[T]) Peek() T {
 
 // Contains checks if the given element exists in the heap.
 func (h *Heap[T]) Contains(element T) bool {
-	_, exists := h.index[element]
+	_, exists := h.find(element)
 	return exists
 }
 
@@ -124,73 +365,280 @@ func (h *Heap[T]) Contains(element T) bool {
 // If there are duplicates, it returns the first occurrence.
 // If the element is not found, it returns the zero value of type T and false.
 func (h *Heap[T]) Get(element T) (T, bool) {
-	indices, exists := h.index[element]
-	if !exists || len(indices) == 0 {
+	i, exists := h.find(element)
+	if !exists {
 		var zero T
 		return zero, false
 	}
-	return h.data[indices[0]], true
+	return h.data[i], true
 }
 
-// Push adds a new element to the heap.
+// Push adds a new element to the heap. If the heap is at its maximum size,
+// see WithMaxSize, the extremal element of the two is evicted.
 func (h *Heap[T]) Push(value T) {
+	if h.full() {
+		h.pushEvict(value)
+		return
+	}
+	h.push(value, nil)
+}
+
+// push adds a new element to the heap, bound to the handle it if handles are
+// in use.
+func (h *Heap[T]) push(value T, it *Item[T]) {
 	if len(h.data) == h.heapSize {
 		h.data = append(h.data, value)
 	} else {
 		h.data[h.heapSize] = value
 	}
 
-	if indices, exists := h.index[value]; exists {
-		h.index[value] = append(indices, indices[0])
-	} else {
-		h.index[value] = []int{h.heapSize}
+	h.addIndex(value, h.heapSize)
+	if h.wait != nil {
+		h.wait.stamp(h.heapSize)
+	}
+	if h.ttl != nil {
+		h.ttl.stamp(h.heapSize)
+	}
+	if h.items != nil {
+		h.trackSlot(h.heapSize, it)
 	}
 	h.heapSize++
-	h.up(h.heapSize - 1) // Restore heap property after insertion
+	if h.heapSize > h.highWater {
+		h.highWater = h.heapSize
+	}
+	if h.stats != nil {
+		h.stats.pushed(h.heapSize)
+	}
+	if h.occupancy != nil {
+		h.occupancy.sample(h.heapSize)
+	}
+	if h.agg != nil {
+		h.agg.added(h, value)
+	}
+	switch {
+	case h.lazy:
+		h.dirty = true
+	case h.stageSize > 0:
+		h.stage()
+	default:
+		levels := h.up(h.heapSize - 1) // Restore heap property after insertion
+		if h.profile != nil {
+			h.profile.record(SiftUp, levels)
+		}
+	}
+	if h.adaptive != nil {
+		h.recordOp(true)
+	}
+	if h.shrink != nil {
+		h.checkShrink()
+	}
+	if h.oplog != nil {
+		h.log(Op[T]{Kind: OpPush, Value: value})
+	}
+	if h.hooks != nil {
+		h.hooks.pushed(value)
+	}
 }
 
 // Pop removes and returns the minimum element from the heap.
 func (h *Heap[T]) Pop() T {
+	h.ensureTop()
 	if h.heapSize == 0 {
 		var zero T
 		return zero
 	}
 	minValue := h.data[0]
-	lastIndex := h.heapSize - 1
-	h.data[0] = h.data[lastIndex]
-	h.index[minValue] = h.index[minValue][1:] // Remove the first index from the slice of indices
-	if len(h.index[minValue]) == 0 {
-		delete(h.index, minValue) // Remove the element from the index hash map if no more indices
+	if h.stats != nil {
+		h.stats.stats.Pops++
 	}
+	if h.wait != nil {
+		h.wait.observe(minValue, 0)
+	}
+	lastIndex := h.heapSize - 1
 	h.swap(0, lastIndex)
+	h.removeIndex(minValue, lastIndex)
+	if h.items != nil {
+		h.releaseSlot(lastIndex)
+	}
 	h.heapSize--
-	h.down(0)
+	h.vacate(lastIndex, lastIndex+1)
+	if h.occupancy != nil {
+		h.occupancy.sample(h.heapSize)
+	}
+	levels := h.down(0)
+	if h.profile != nil {
+		h.profile.record(SiftDown, levels)
+	}
+	if h.agg != nil {
+		h.agg.removed(h, minValue)
+	}
+	if h.adaptive != nil {
+		h.recordOp(false)
+	}
+	if h.shrink != nil {
+		h.checkShrink()
+	}
+	if h.oplog != nil {
+		h.log(Op[T]{Kind: OpPop, Value: minValue})
+	}
+	if h.hooks != nil {
+		h.hooks.popped(minValue)
+	}
 	return minValue
 }
 
+// PushPop pushes value and then pops the extremal element in a single sift.
+// If value would itself be popped, the heap is left untouched. It suits top-k
+// maintenance, where most new candidates are rejected immediately.
+func (h *Heap[T]) PushPop(value T) T {
+	h.ensureTop()
+	if h.heapSize == 0 || !h.lessFunc(h.data[0], value) {
+		return value
+	}
+	top := h.replaceRoot(value)
+	if h.hooks != nil {
+		h.hooks.pushed(value)
+		h.hooks.popped(top)
+	}
+	return top
+}
+
+// Replace pops the extremal element and then pushes value in a single sift,
+// returning the popped element. Unlike PushPop, the returned element is never
+// value itself. If the heap is empty, it pushes value and returns the zero
+// value of type T.
+func (h *Heap[T]) Replace(value T) T {
+	h.ensureTop()
+	if h.heapSize == 0 {
+		h.Push(value)
+		var zero T
+		return zero
+	}
+	top := h.replaceRoot(value)
+	if h.hooks != nil {
+		h.hooks.pushed(value)
+		h.hooks.popped(top)
+	}
+	return top
+}
+
+// replaceRoot replaces the root with value, sifts it down and returns the old
+// root, accounting for the change as a pop followed by a push.
+func (h *Heap[T]) replaceRoot(value T) T {
+	top := h.data[0]
+	if h.stats != nil {
+		h.stats.stats.Pops++
+		h.stats.pushed(h.heapSize)
+	}
+	if h.wait != nil {
+		h.wait.observe(top, 0)
+		h.wait.stamp(0)
+	}
+	if h.ttl != nil {
+		h.ttl.stamp(0)
+	}
+	if h.items != nil {
+		h.releaseSlot(0)
+	}
+	h.replaceAt(0, value)
+	levels := h.down(0)
+	if h.profile != nil {
+		h.profile.record(SiftDown, levels)
+	}
+	if h.agg != nil {
+		h.agg.added(h, value)
+		h.agg.removed(h, top)
+	}
+	if h.adaptive != nil {
+		h.recordOp(false)
+		h.recordOp(true)
+	}
+	if h.oplog != nil {
+		h.log(Op[T]{Kind: OpPop, Value: top})
+		h.log(Op[T]{Kind: OpPush, Value: value})
+	}
+	return top
+}
+
+// Remove removes one occurrence of element from the heap in O(d log_d n),
+// restoring the heap property from the vacated slot. It returns false if the
+// element is not in the heap.
+func (h *Heap[T]) Remove(element T) bool {
+	h.mergeStaged()
+	i, exists := h.find(element)
+	if !exists {
+		return false
+	}
+	h.removeAt(i)
+	return true
+}
+
+// removeAt removes and returns the element at index i and restores the heap
+// property by sifting the element moved into its place up or down. Staged
+// elements must already have been merged.
+func (h *Heap[T]) removeAt(i int) T {
+	value := h.data[i]
+	lastIndex := h.heapSize - 1
+	h.swap(i, lastIndex)
+	h.removeIndex(value, lastIndex)
+	if h.items != nil {
+		h.releaseSlot(lastIndex)
+	}
+	h.heapSize--
+	h.vacate(lastIndex, lastIndex+1)
+	if h.occupancy != nil {
+		h.occupancy.sample(h.heapSize)
+	}
+	if i < h.heapSize && !h.dirty {
+		h.fix(i)
+	}
+	if h.agg != nil {
+		h.agg.removed(h, value)
+	}
+	if h.oplog != nil {
+		h.log(Op[T]{Kind: OpRemove, Value: value})
+	}
+	return value
+}
+
+// fix restores the heap property after the element at index i has changed.
+func (h *Heap[T]) fix(i int) {
+	if h.up(i) == 0 {
+		h.down(i)
+	}
+}
+
 // up restores the heap property by bubbling an element up the tree.
-func (h *Heap[T]) up(i int) {
+// It returns the number of levels the element moved.
+func (h *Heap[T]) up(i int) int {
+	levels := 0
 	for i > 0 && h.lessFunc(h.data[i], h.data[h.parent(i)]) {
 		h.swap(i, h.parent(i))
 		i = h.parent(i)
+		levels++
 	}
+	return levels
 }
 
 // down restores the heap property by moving an element down the tree.
-func (h *Heap[T]) down(i int) {
+// It returns the number of levels the element moved.
+func (h *Heap[T]) down(i int) int {
+	if h.strategy == SiftBottomUp {
+		return h.downBottomUp(i)
+	}
+	levels := 0
 	for {
-		smallest := i // Assume the current node is the smallest
-		for k := 1; k <= h.d && h.child(i, k) < h.heapSize; k++ {
-			childIndex := h.child(i, k)
-			if h.lessFunc(h.data[childIndex], h.data[smallest]) {
-				smallest = childIndex
-			}
+		var smallest int
+		if h.minChild != nil {
+			smallest = h.minChild(h, i)
+		} else {
+			smallest = h.minChildLoop(i)
 		}
-
 		if smallest == i {
-			break // Heap property is satisfied
+			return levels // Heap property is satisfied
 		}
 		h.swap(i, smallest)
 		i = smallest
+		levels++
 	}
 }
@@ -1,14 +1,16 @@
 package heap
 
 import (
+	"math/rand"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
-	"golang.org/x/exp/constraints"
 )
 
 func TestHeapOperations(t *testing.T) {
-	type heapOperation[T constraints.Ordered] struct {
+	type heapOperation[T comparable] struct {
 		operation string // "push", "pop", or "peek"
 		value     T      // value to push (ignored for pop and peek)
 		want      T      // expected result for peek or pop
@@ -253,6 +255,42 @@ func TestHeapContains(t *testing.T) {
 	assert.False(t, heap.Contains(1), "Contains(1) returned true, want false")
 }
 
+func TestHeapStructElements(t *testing.T) {
+	t.Parallel()
+
+	type task struct {
+		name     string
+		priority int
+	}
+	heap := NewHeap[task](3, func(a, b task) bool { return a.priority < b.priority })
+	for _, tk := range []task{{"index", 3}, {"flush", 1}, {"compact", 2}} {
+		heap.Push(tk)
+	}
+	assert.True(t, heap.Contains(task{"compact", 2}))
+	assert.True(t, heap.Update(task{"compact", 2}, task{"compact", 0}))
+
+	for _, want := range []string{"compact", "flush", "index"} {
+		assert.Equal(t, want, heap.Pop().name)
+	}
+}
+
+func TestNewMinMax(t *testing.T) {
+	t.Parallel()
+
+	min := NewMin[string](4)
+	max := NewMax[string](4)
+	for _, v := range []string{"pear", "apple", "fig", "kiwi"} {
+		min.Push(v)
+		max.Push(v)
+	}
+	for _, want := range []string{"apple", "fig", "kiwi", "pear"} {
+		assert.Equal(t, want, min.Pop())
+	}
+	for _, want := range []string{"pear", "kiwi", "fig", "apple"} {
+		assert.Equal(t, want, max.Pop())
+	}
+}
+
 func TestHeapGet(t *testing.T) {
 	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
 	heap.Push(5)
@@ -284,3 +322,299 @@ func TestHeapGet(t *testing.T) {
 	assert.False(t, ok, "Get(1) returned true, want false")
 	assert.Zero(t, val, "Get(1) returned %d, want 0", val)
 }
+
+func TestHeapIndexDuplicates(t *testing.T) {
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b })
+	checkIndex := func() {
+		recorded := 0
+		for v, indices := range heap.index {
+			for _, i := range indices {
+				assert.Less(t, i, heap.heapSize, "index of %d past the end of the heap", v)
+				assert.Equal(t, v, heap.data[i], "index of %d points at another element", v)
+			}
+			recorded += len(indices)
+		}
+		assert.Equal(t, heap.heapSize, recorded, "index does not record every element")
+	}
+
+	for _, v := range []int{5, 1, 5, 3, 1, 5, 2, 1, 4, 3} {
+		heap.Push(v)
+		checkIndex()
+	}
+	for _, want := range []int{1, 1, 1, 2, 3, 3, 4, 5, 5, 5} {
+		assert.Equal(t, want, heap.Pop())
+		checkIndex()
+	}
+	assert.Empty(t, heap.index)
+}
+
+func TestHeapRemove(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []int{2, 3, 4} {
+		rng := rand.New(rand.NewSource(int64(d)))
+		heap := NewHeap[int](d, func(a, b int) bool { return a < b })
+		counts := make(map[int]int)
+		for i := 0; i < 300; i++ {
+			v := rng.Intn(60)
+			heap.Push(v)
+			counts[v]++
+		}
+
+		for i := 0; i < 150; i++ {
+			v := rng.Intn(70)
+			assert.Equal(t, counts[v] > 0, heap.Remove(v), "d=%d Remove(%d)", d, v)
+			if counts[v] > 0 {
+				counts[v]--
+			}
+		}
+
+		var want []int
+		for v, n := range counts {
+			for ; n > 0; n-- {
+				want = append(want, v)
+			}
+		}
+		sort.Ints(want)
+		for _, v := range want {
+			assert.Equal(t, v, heap.Pop(), "d=%d", d)
+		}
+		assert.Empty(t, heap.index, "d=%d index not empty after draining", d)
+	}
+}
+
+func TestHeapLenCapClear(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b }, WithCapacity[int](32))
+	assert.True(t, heap.IsEmpty())
+	assert.Zero(t, heap.Len())
+	assert.Equal(t, 32, heap.Cap())
+
+	for i := 0; i < 20; i++ {
+		heap.Push(i)
+	}
+	assert.False(t, heap.IsEmpty())
+	assert.Equal(t, 20, heap.Len())
+
+	data := &heap.data[0]
+	heap.Clear()
+	assert.True(t, heap.IsEmpty())
+	assert.False(t, heap.Contains(3))
+	assert.Empty(t, heap.index)
+	assert.Equal(t, 32, heap.Cap())
+
+	heap.Push(7)
+	assert.Same(t, data, &heap.data[0], "Clear reallocated the backing array")
+	assert.Equal(t, 7, heap.Pop())
+}
+
+func TestHeapReset(t *testing.T) {
+	t.Parallel()
+
+	clock := NewVirtualClock(time.Unix(0, 0))
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b },
+		WithStats[int](), WithWaitTracking[int](clock, nil), WithOccupancy[int](0.5), WithSiftProfile[int](nil))
+	for i := 0; i < 100; i++ {
+		heap.Push(100 - i)
+	}
+	it := heap.PushHandle(1000)
+	for i := 0; i < 10; i++ {
+		heap.Pop()
+	}
+	capacity := heap.Cap()
+
+	heap.Reset()
+	assert.True(t, heap.IsEmpty())
+	assert.False(t, heap.Contains(50))
+	_, ok := it.Value()
+	assert.False(t, ok, "handle still valid after Reset")
+	assert.Equal(t, capacity, heap.Cap(), "Reset released the backing array")
+	assert.Equal(t, Stats{}, heap.Stats())
+	assert.Equal(t, WaitStats{}, heap.WaitStats())
+	assert.Equal(t, SiftProfile{}, heap.SiftProfile())
+	assert.Zero(t, heap.AverageOccupancy())
+	assert.Zero(t, heap.HighWaterMark())
+
+	heap.PushAll(3, 1, 2)
+	assert.Equal(t, 1, heap.Pop())
+	assert.Equal(t, uint64(1), heap.Stats().Pops)
+	assert.NoError(t, heap.Verify())
+}
+
+func TestHeapResetAllocations(t *testing.T) {
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b })
+	fill := func() {
+		for i := 0; i < 100; i++ {
+			heap.Push(i)
+		}
+		heap.Reset()
+	}
+	fill()
+
+	allocs := testing.AllocsPerRun(100, fill)
+	assert.Zero(t, allocs, "refilling a reset heap allocated")
+}
+
+func TestNewHeapFromSlice(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []int{2, 3, 4} {
+		rng := rand.New(rand.NewSource(int64(d)))
+		items := make([]int, 1000)
+		for i := range items {
+			items[i] = rng.Intn(100)
+		}
+		want := append([]int(nil), items...)
+		sort.Ints(want)
+
+		heap := NewHeapFromSlice(d, func(a, b int) bool { return a < b }, items)
+		assert.Equal(t, len(items), heap.Len())
+		assert.Same(t, &items[0], &heap.data[0], "d=%d items were copied", d)
+		assert.True(t, heap.Contains(want[500]))
+		for _, v := range want {
+			assert.Equal(t, v, heap.Pop(), "d=%d", d)
+		}
+		assert.Empty(t, heap.index, "d=%d index not empty after draining", d)
+	}
+}
+
+func TestHeapPushPopReplace(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b })
+	assert.Equal(t, 5, heap.PushPop(5), "PushPop on an empty heap")
+	assert.True(t, heap.IsEmpty())
+	assert.Zero(t, heap.Replace(5), "Replace on an empty heap")
+	assert.Equal(t, 1, heap.Len())
+
+	for _, v := range []int{8, 2, 6} {
+		heap.Push(v)
+	}
+	assert.Equal(t, 1, heap.PushPop(1), "value ordering first is returned directly")
+	assert.Equal(t, 2, heap.PushPop(7))
+	assert.Equal(t, 5, heap.Replace(0))
+	assert.True(t, heap.Contains(7))
+	assert.False(t, heap.Contains(2))
+
+	for _, want := range []int{0, 6, 7, 8} {
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.Empty(t, heap.index)
+}
+
+func TestHeapPushPopTopK(t *testing.T) {
+	t.Parallel()
+
+	// Keep the 10 largest values in a min-heap of size 10.
+	rng := rand.New(rand.NewSource(1))
+	values := rng.Perm(1000)
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithAggregates(func(v int) float64 { return float64(v) }))
+	for _, v := range values[:10] {
+		heap.Push(v)
+	}
+	for _, v := range values[10:] {
+		heap.PushPop(v)
+	}
+	assert.Equal(t, float64(990+991+992+993+994+995+996+997+998+999), heap.Aggregates().Sum)
+	for want := 990; want < 1000; want++ {
+		assert.Equal(t, want, heap.Pop())
+	}
+}
+
+func TestHeapPushPopAllocations(t *testing.T) {
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b })
+	for i := 0; i < 1000; i++ {
+		heap.Push(i)
+	}
+
+	next := 1000
+	allocs := testing.AllocsPerRun(1000, func() {
+		heap.Pop()
+		heap.Push(next)
+		next++
+	})
+	assert.Zero(t, allocs, "steady-state Push/Pop allocated")
+}
+
+func BenchmarkHeapPushPop(b *testing.B) {
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b })
+	for i := 0; i < 1<<12; i++ {
+		heap.Push(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(heap.Pop() + 1<<12)
+	}
+}
+
+func BenchmarkHeapPushPopWithoutIndex(b *testing.B) {
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithoutIndex[int]())
+	for i := 0; i < 1<<12; i++ {
+		heap.Push(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(heap.Pop() + 1<<12)
+	}
+}
+
+func TestHeapShiftIndexMath(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []int{2, 3, 4, 6, 8, 16} {
+		shifted := NewHeap[int](d, func(a, b int) bool { return a < b })
+		divided := &Heap[int]{d: d}
+		assert.Equal(t, d&(d-1) == 0, shifted.shift > 0, "d=%d", d)
+		for i := 1; i < 1000; i++ {
+			assert.Equal(t, divided.parent(i), shifted.parent(i), "d=%d i=%d", d, i)
+			for k := 1; k <= d; k++ {
+				assert.Equal(t, divided.child(i, k), shifted.child(i, k), "d=%d i=%d k=%d", d, i, k)
+			}
+		}
+	}
+}
+
+func benchmarkHeapPop(b *testing.B, heap *Heap[int]) {
+	const n = 1 << 14
+	for i := 0; i < n; i++ {
+		heap.Push(i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(heap.Pop() + n)
+	}
+}
+
+func BenchmarkHeapPopShiftD4(b *testing.B) {
+	benchmarkHeapPop(b, NewHeap[int](4, func(a, b int) bool { return a < b }))
+}
+
+func BenchmarkHeapPopDivideD4(b *testing.B) {
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b })
+	heap.shift = 0 // Force the general multiply/divide path
+	benchmarkHeapPop(b, heap)
+}
+
+func TestHeapWithBackingSlice(t *testing.T) {
+	t.Parallel()
+
+	buf := make([]int, 3, 64)
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithBackingSlice(buf))
+	for i := 64; i > 0; i-- {
+		heap.Push(i)
+	}
+	assert.Same(t, &buf[:1][0], &heap.data[0], "heap reallocated within the supplied capacity")
+	assert.Equal(t, 1, buf[:1][0])
+
+	heap.Push(0)
+	assert.NotSame(t, &buf[:1][0], &heap.data[0], "heap did not grow past the supplied capacity")
+	for want := 0; want <= 64; want++ {
+		assert.Equal(t, want, heap.Pop())
+	}
+}
@@ -0,0 +1,115 @@
+// Package heapmetrics exports the state of a heap as metrics: its length, the
+// rate at which elements are pushed and popped, and how long the head and the
+// oldest element have been queued. A Collector samples a heap on demand, so
+// nothing is recorded between scrapes.
+//
+// Collector.Var publishes samples through expvar. Other monitoring systems can
+// read Collector.Collect from their own callbacks; for Prometheus, register a
+// GaugeFunc per Sample field.
+package heapmetrics
+
+import (
+	"expvar"
+	"sync"
+	"time"
+
+	heap "github.com/ahrav/go-d-ary-heap"
+)
+
+// Source is a heap a Collector can sample. *heap.ConcurrentHeap satisfies it,
+// as does *heap.Heap when it is only accessed under a lock passed to
+// WithLocker. Push and pop counts require the heap to be created with
+// heap.WithStats, and waits require heap.WithWaitTracking; without them those
+// fields read zero.
+type Source interface {
+	Len() int
+	Stats() heap.Stats
+	HeadWait() time.Duration
+	OldestWait() time.Duration
+}
+
+// Sample is a point-in-time reading of a heap.
+type Sample struct {
+	Len        int           `json:"len"`
+	Pushes     uint64        `json:"pushes"`
+	Pops       uint64        `json:"pops"`
+	PushRate   float64       `json:"push_rate"` // Pushes per second since the previous sample
+	PopRate    float64       `json:"pop_rate"`  // Pops per second since the previous sample
+	HeadWait   time.Duration `json:"head_wait_ns"`
+	OldestWait time.Duration `json:"oldest_wait_ns"`
+}
+
+// Collector samples a Source and derives rates from successive samples. It is
+// safe for concurrent use.
+type Collector struct {
+	source Source
+	locker sync.Locker
+	clock  heap.Clock
+
+	mu   sync.Mutex
+	last Sample    // Previous sample, for rates
+	at   time.Time // Time of the previous sample, zero before the first
+}
+
+// Option configures a Collector.
+type Option func(*Collector)
+
+// WithLocker is an option that holds l while the source is read, for a heap
+// that is not safe for concurrent use on its own.
+func WithLocker(l sync.Locker) Option {
+	return func(c *Collector) {
+		c.locker = l
+	}
+}
+
+// WithClock is an option that measures the time between samples with clock
+// instead of the system clock.
+func WithClock(clock heap.Clock) Option {
+	return func(c *Collector) {
+		c.clock = clock
+	}
+}
+
+// New creates a Collector sampling source.
+func New(source Source, options ...Option) *Collector {
+	c := &Collector{source: source, clock: heap.SystemClock{}}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Collect samples the source. Rates cover the interval since the previous
+// call and are zero on the first.
+func (c *Collector) Collect() Sample {
+	if c.locker != nil {
+		c.locker.Lock()
+	}
+	stats := c.source.Stats()
+	s := Sample{
+		Len:        c.source.Len(),
+		Pushes:     stats.Pushes,
+		Pops:       stats.Pops,
+		HeadWait:   c.source.HeadWait(),
+		OldestWait: c.source.OldestWait(),
+	}
+	if c.locker != nil {
+		c.locker.Unlock()
+	}
+
+	now := c.clock.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elapsed := now.Sub(c.at).Seconds(); !c.at.IsZero() && elapsed > 0 {
+		s.PushRate = float64(s.Pushes-c.last.Pushes) / elapsed
+		s.PopRate = float64(s.Pops-c.last.Pops) / elapsed
+	}
+	c.last, c.at = s, now
+	return s
+}
+
+// Var returns an expvar.Var that reports a fresh Sample as JSON each time it
+// is read, for use with expvar.Publish.
+func (c *Collector) Var() expvar.Var {
+	return expvar.Func(func() any { return c.Collect() })
+}
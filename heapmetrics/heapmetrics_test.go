@@ -0,0 +1,56 @@
+package heapmetrics
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	heap "github.com/ahrav/go-d-ary-heap"
+	"github.com/stretchr/testify/assert"
+)
+
+// stepClock is a heap.Clock that only moves when told to.
+type stepClock struct{ now time.Time }
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	clock := &stepClock{now: time.Unix(0, 0)}
+	queue := heap.NewConcurrentHeap[int](4, func(a, b int) bool { return a < b },
+		heap.WithStats[int](), heap.WithWaitTracking[int](clock, nil))
+	collector := New(queue, WithClock(clock))
+
+	queue.Push(5)
+	clock.now = clock.now.Add(time.Second)
+	queue.Push(1)
+	first := collector.Collect()
+	assert.Equal(t, Sample{Len: 2, Pushes: 2, HeadWait: 0, OldestWait: time.Second}, first)
+
+	clock.now = clock.now.Add(2 * time.Second)
+	for v := 10; v < 16; v++ {
+		queue.Push(v)
+	}
+	queue.Pop()
+	second := collector.Collect()
+	assert.Equal(t, 7, second.Len)
+	assert.Equal(t, 3.0, second.PushRate)
+	assert.Equal(t, 0.5, second.PopRate)
+	assert.Equal(t, 3*time.Second, second.HeadWait, "5 is now the head")
+}
+
+func TestCollectorVar(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	h := heap.NewHeap[int](2, func(a, b int) bool { return a < b }, heap.WithStats[int]())
+	h.Push(3)
+	collector := New(h, WithLocker(&mu))
+
+	var got map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(collector.Var().String()), &got))
+	assert.Equal(t, float64(1), got["len"])
+	assert.Equal(t, float64(1), got["pushes"])
+}
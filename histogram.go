@@ -0,0 +1,32 @@
+package heap
+
+import "sort"
+
+// Histogram counts the queued elements per priority bucket in a single pass.
+// The buckets are upper bounds listed in heap order (the order defined by the
+// less function); an element falls into the first bucket it does not come
+// after. The returned slice has len(buckets)+1 entries, the last counting
+// elements that come after every bucket.
+func (h *Heap[T]) Histogram(buckets []T) []int {
+	h.purge()
+	counts := make([]int, len(buckets)+1)
+	for _, v := range h.data[:h.heapSize] {
+		i := sort.Search(len(buckets), func(i int) bool { return !h.lessFunc(buckets[i], v) })
+		counts[i]++
+	}
+	return counts
+}
+
+// HistogramFunc counts the queued elements per bucket as assigned by bucketOf,
+// which must return a bucket number in [0, n). Elements for which bucketOf
+// returns a number outside that range are not counted.
+func (h *Heap[T]) HistogramFunc(n int, bucketOf func(T) int) []int {
+	h.purge()
+	counts := make([]int, n)
+	for _, v := range h.data[:h.heapSize] {
+		if i := bucketOf(v); i >= 0 && i < n {
+			counts[i]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,57 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapHistogram(t *testing.T) {
+	tests := []struct {
+		name    string
+		less    func(a, b int) bool
+		buckets []int
+		want    []int
+	}{
+		{
+			name:    "MinHeap",
+			less:    func(a, b int) bool { return a < b },
+			buckets: []int{2, 5, 8},
+			want:    []int{2, 3, 3, 2},
+		},
+		{
+			name:    "MaxHeap",
+			less:    func(a, b int) bool { return a > b },
+			buckets: []int{8, 5, 2},
+			want:    []int{3, 3, 3, 1},
+		},
+		{
+			name: "No buckets",
+			less: func(a, b int) bool { return a < b },
+			want: []int{10},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			heap := NewHeap[int](3, tt.less)
+			for i := 1; i <= 10; i++ {
+				heap.Push(i)
+			}
+			assert.Equal(t, tt.want, heap.Histogram(tt.buckets))
+		})
+	}
+}
+
+func TestHeapHistogramFunc(t *testing.T) {
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	for i := 0; i < 10; i++ {
+		heap.Push(i)
+	}
+
+	counts := heap.HistogramFunc(3, func(v int) int { return v / 3 })
+	assert.Equal(t, []int{3, 3, 3}, counts, "value 9 falls outside the buckets and is not counted")
+}
@@ -0,0 +1,61 @@
+package heap
+
+// mutationHooks holds the callbacks registered by WithOnPush, WithOnPop and
+// WithOnEvict.
+type mutationHooks[T comparable] struct {
+	onPush  func(T)
+	onPop   func(T)
+	onEvict func(T)
+}
+
+// WithOnPush is an option that calls fn with every element added to the heap,
+// by any method, after it has been added.
+func WithOnPush[T comparable](fn func(T)) Option[T] {
+	return func(h *Heap[T]) {
+		h.mutationHooks().onPush = fn
+	}
+}
+
+// WithOnPop is an option that calls fn with every extremal element removed by
+// Pop, PushPop or Replace, after it has been removed. Elements taken out by
+// Remove, RemoveIf or eviction are not reported.
+func WithOnPop[T comparable](fn func(T)) Option[T] {
+	return func(h *Heap[T]) {
+		h.mutationHooks().onPop = fn
+	}
+}
+
+// WithOnEvict is an option that calls fn with every element the heap discards
+// to stay within the maximum size set by WithMaxSize, which may be the pushed
+// element itself.
+func WithOnEvict[T comparable](fn func(T)) Option[T] {
+	return func(h *Heap[T]) {
+		h.mutationHooks().onEvict = fn
+	}
+}
+
+// mutationHooks returns the heap's hooks, allocating them on first use.
+func (h *Heap[T]) mutationHooks() *mutationHooks[T] {
+	if h.hooks == nil {
+		h.hooks = &mutationHooks[T]{}
+	}
+	return h.hooks
+}
+
+func (m *mutationHooks[T]) pushed(v T) {
+	if m.onPush != nil {
+		m.onPush(v)
+	}
+}
+
+func (m *mutationHooks[T]) popped(v T) {
+	if m.onPop != nil {
+		m.onPop(v)
+	}
+}
+
+func (m *mutationHooks[T]) evicted(v T) {
+	if m.onEvict != nil {
+		m.onEvict(v)
+	}
+}
@@ -0,0 +1,36 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapMutationHooks(t *testing.T) {
+	t.Parallel()
+
+	var pushed, popped, evicted []int
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b },
+		WithMaxSize[int](3),
+		WithOnPush(func(v int) { pushed = append(pushed, v) }),
+		WithOnPop(func(v int) { popped = append(popped, v) }),
+		WithOnEvict(func(v int) { evicted = append(evicted, v) }))
+
+	heap.PushAll(5, 3, 8)
+	heap.Push(1) // Rejected: it would be evicted first
+	heap.Push(9) // Evicts 3
+	v, ok := heap.PushEvict(4)
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+	assert.Equal(t, []int{5, 3, 8, 9}, pushed)
+	assert.Equal(t, []int{1, 3, 4}, evicted)
+	assert.Empty(t, popped)
+
+	assert.Equal(t, 5, heap.Pop())
+	assert.Equal(t, 8, heap.Replace(7))
+	assert.Equal(t, 7, heap.PushPop(10))
+	heap.Remove(9)
+	assert.Equal(t, []int{5, 8, 7}, popped)
+	assert.Equal(t, []int{5, 3, 8, 9, 7, 10}, pushed)
+	assert.Equal(t, []int{1, 3, 4}, evicted)
+}
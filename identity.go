@@ -0,0 +1,81 @@
+package heap
+
+// keyIndex records element positions under a key derived from each element,
+// so lookups match elements by key rather than by value.
+type keyIndex[T any] interface {
+	add(element T, i int)
+	move(element T, from, to int)
+	remove(element T, i int)
+	find(element T) (int, bool)
+	clear()
+	reserve(capacity int)
+	clone() keyIndex[T]
+	compact()
+	verify(data []T) error
+	size() int
+}
+
+// identityIndex is a keyIndex keyed by a caller-supplied identity function.
+type identityIndex[T any, K comparable] struct {
+	identity func(T) K
+	index    map[K][]int
+	spare    [][]int
+}
+
+// WithIdentity is an option that keys the index by identity(element) instead
+// of the element's full value. Contains, Get, Remove and Update then match any
+// element with the same identity, so a struct can be looked up by an ID field
+// alone, and an element whose other fields have changed while it was queued
+// can still be found. identity must return the same key for an element for as
+// long as it is in the heap.
+func WithIdentity[T comparable, K comparable](identity func(T) K) Option[T] {
+	return func(h *Heap[T]) {
+		h.keyed = &identityIndex[T, K]{identity: identity, index: make(map[K][]int, cap(h.data))}
+		h.index, h.hash, h.hashed = nil, nil, nil
+	}
+}
+
+func (x *identityIndex[T, K]) add(element T, i int) {
+	indexAdd(x.index, x.identity(element), i, &x.spare)
+}
+
+func (x *identityIndex[T, K]) move(element T, from, to int) {
+	indexMove(x.index, x.identity(element), from, to)
+}
+
+func (x *identityIndex[T, K]) remove(element T, i int) {
+	indexRemove(x.index, x.identity(element), i, &x.spare)
+}
+
+func (x *identityIndex[T, K]) find(element T) (int, bool) {
+	indices := x.index[x.identity(element)]
+	if len(indices) == 0 {
+		return 0, false
+	}
+	return indices[0], true
+}
+
+func (x *identityIndex[T, K]) clear() {
+	indexClear(x.index, &x.spare)
+}
+
+func (x *identityIndex[T, K]) reserve(capacity int) {
+	x.index = make(map[K][]int, capacity)
+}
+
+func (x *identityIndex[T, K]) clone() keyIndex[T] {
+	return &identityIndex[T, K]{identity: x.identity, index: cloneIndex(x.index)}
+}
+
+func (x *identityIndex[T, K]) compact() {
+	x.index = compactIndex(x.index)
+	x.spare = nil
+}
+
+func (x *identityIndex[T, K]) verify(data []T) error {
+	return verifyIndex(x.index, len(data), func(i int) K { return x.identity(data[i]) })
+}
+
+func (x *identityIndex[T, K]) size() int {
+	return len(x.index)
+}
@@ -0,0 +1,40 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithIdentity(t *testing.T) {
+	t.Parallel()
+
+	type task struct {
+		id       int
+		priority int
+	}
+	heap := NewHeap[task](2, func(a, b task) bool { return a.priority < b.priority },
+		WithIdentity[task](func(tk task) int { return tk.id }))
+	for _, tk := range []task{{1, 30}, {2, 10}, {3, 20}} {
+		heap.Push(tk)
+	}
+	assert.Nil(t, heap.index)
+
+	// Lookups only need the identity.
+	assert.True(t, heap.Contains(task{id: 3}))
+	got, ok := heap.Get(task{id: 1})
+	assert.True(t, ok)
+	assert.Equal(t, task{1, 30}, got)
+
+	assert.True(t, heap.Update(task{id: 1}, task{1, 5}))
+	assert.True(t, heap.Remove(task{id: 2}))
+	assert.False(t, heap.Remove(task{id: 2}))
+
+	clone := heap.Clone()
+	assert.Equal(t, task{1, 5}, heap.Pop())
+	assert.True(t, clone.Contains(task{id: 1}))
+	assert.False(t, heap.Contains(task{id: 1}))
+
+	assert.Equal(t, task{3, 20}, heap.Pop())
+	assert.True(t, heap.IsEmpty())
+}
@@ -0,0 +1,126 @@
+package heap
+
+import "hash/maphash"
+
+// WithHashIndex is an option that keys the index map by a 64-bit hash of each
+// element instead of the element itself. Elements whose hashes collide share
+// an entry and are told apart by comparing the stored values, so hash only
+// needs to be consistent, not collision-free. For heaps of strings this halves
+// the size of each index key and keeps the map from holding a second
+// reference to every string; see HashString.
+func WithHashIndex[T comparable](hash func(T) uint64) Option[T] {
+	return func(h *Heap[T]) {
+		h.hash = hash
+		h.hashed = make(map[uint64][]int, cap(h.data))
+		h.index, h.keyed = nil, nil
+	}
+}
+
+// WithoutIndex is an option that stops the heap from recording the position
+// of each element. Push and Pop then skip the map updates on every swap, which
+// dominate their cost for small element types, but Contains, Get, Remove and
+// Update fall back to an O(n) scan of the heap. Use it when elements are only
+// ever pushed and popped.
+func WithoutIndex[T comparable]() Option[T] {
+	return func(h *Heap[T]) {
+		h.noIndex = true
+		h.index, h.hashed, h.keyed = nil, nil, nil
+	}
+}
+
+// newIndex allocates an empty index sized for capacity elements, keyed by hash
+// or identity if one is set. It does nothing if the heap is not indexed.
+func (h *Heap[T]) newIndex(capacity int) {
+	switch {
+	case h.noIndex:
+	case h.keyed != nil:
+		h.keyed.reserve(capacity)
+	case h.hash != nil:
+		h.hashed = make(map[uint64][]int, capacity)
+	default:
+		h.index = make(map[T][]int, capacity)
+	}
+}
+
+// clearIndex forgets every recorded position.
+func (h *Heap[T]) clearIndex() {
+	switch {
+	case h.keyed != nil:
+		h.keyed.clear()
+	case h.hash != nil:
+		indexClear(h.hashed, &h.spare)
+	default:
+		indexClear(h.index, &h.spare)
+	}
+}
+
+// valueIndexed reports whether the heap uses the default index keyed by
+// element value.
+func (h *Heap[T]) valueIndexed() bool {
+	return !h.noIndex && h.keyed == nil && h.hash == nil
+}
+
+// rebuildIndex records the position of every element afresh, for operations
+// that replace the contents wholesale.
+func (h *Heap[T]) rebuildIndex() {
+	h.clearIndex()
+	for i, v := range h.data[:h.heapSize] {
+		h.addIndex(v, i)
+	}
+}
+
+// HashString returns a hash function for strings suitable for WithHashIndex.
+func HashString() func(string) uint64 {
+	seed := maphash.MakeSeed()
+	return func(s string) uint64 { return maphash.String(seed, s) }
+}
+
+// indexAdd records position i under key. New keys reuse a slice released by
+// indexRemove, so steady-state pushes do not allocate.
+func indexAdd[K comparable](index map[K][]int, key K, i int, spare *[][]int) {
+	indices, exists := index[key]
+	if !exists && len(*spare) > 0 {
+		indices = (*spare)[len(*spare)-1]
+		*spare = (*spare)[:len(*spare)-1]
+	}
+	index[key] = append(indices, i)
+}
+
+// indexMove replaces position from with position to under key.
+func indexMove[K comparable](index map[K][]int, key K, from, to int) {
+	for k, idx := range index[key] {
+		if idx == from {
+			index[key][k] = to
+			return
+		}
+	}
+}
+
+// indexClear removes every key from index, keeping the released position
+// slices for reuse by indexAdd so that refilling the index does not allocate.
+func indexClear[K comparable](index map[K][]int, spare *[][]int) {
+	for _, indices := range index {
+		*spare = append(*spare, indices[:0])
+	}
+	clear(index)
+}
+
+// indexRemove forgets position i under key, deleting the key and releasing its
+// slice for reuse once no positions remain.
+func indexRemove[K comparable](index map[K][]int, key K, i int, spare *[][]int) {
+	indices := index[key]
+	for k, idx := range indices {
+		if idx == i {
+			last := len(indices) - 1
+			indices[k] = indices[last]
+			indices = indices[:last]
+			break
+		}
+	}
+	if len(indices) == 0 {
+		delete(index, key)
+		*spare = append(*spare, indices)
+		return
+	}
+	index[key] = indices
+}
@@ -0,0 +1,67 @@
+package heap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashIndex(t *testing.T) {
+	long := func(s string) string { return strings.Repeat(s, 100) }
+	heap := NewHeap[string](3, func(a, b string) bool { return a < b }, WithHashIndex[string](HashString()))
+
+	for _, s := range []string{"d", "b", "a", "c", "b"} {
+		heap.Push(long(s))
+	}
+	assert.Nil(t, heap.index)
+	assert.True(t, heap.Contains(long("b")))
+	assert.False(t, heap.Contains(long("e")))
+
+	got, ok := heap.Get(long("c"))
+	assert.True(t, ok)
+	assert.Equal(t, long("c"), got)
+
+	assert.Equal(t, long("a"), heap.Pop())
+	assert.Equal(t, long("b"), heap.Pop())
+	assert.True(t, heap.Contains(long("b")), "duplicate lost after one Pop")
+	assert.Equal(t, long("b"), heap.Pop())
+	assert.False(t, heap.Contains(long("b")))
+}
+
+func TestHashIndexCollisions(t *testing.T) {
+	// A constant hash forces every element into the same entry.
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b }, WithHashIndex[int](func(int) uint64 { return 7 }))
+	for _, v := range []int{5, 3, 8, 1} {
+		heap.Push(v)
+	}
+
+	assert.True(t, heap.Contains(8))
+	assert.False(t, heap.Contains(2))
+	assert.Equal(t, 1, heap.Pop())
+	assert.False(t, heap.Contains(1))
+	assert.Len(t, heap.hashed[7], 3)
+}
+
+func TestWithoutIndex(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithCapacity[int](8), WithoutIndex[int]())
+	for _, v := range []int{5, 3, 8, 1, 3} {
+		heap.Push(v)
+	}
+	assert.Nil(t, heap.index)
+	assert.Nil(t, heap.hashed)
+
+	// Lookups still work by scanning the heap.
+	assert.True(t, heap.Contains(8))
+	assert.False(t, heap.Contains(2))
+	assert.True(t, heap.Remove(3))
+	assert.True(t, heap.Update(8, 0))
+	assert.False(t, heap.Update(8, 9))
+
+	for _, want := range []int{0, 1, 3, 5} {
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.Nil(t, heap.index)
+}
@@ -0,0 +1,111 @@
+package heap
+
+// IndexedHeap is a d-ary heap of values addressed by unique keys. Setting a
+// key inserts its value or updates it in place, which is the operation
+// Dijkstra's algorithm, A* and timer maps need. The values only need an
+// ordering function and need not be comparable.
+type IndexedHeap[K comparable, T any] struct {
+	entries []indexedEntry[K, T] // Key and value of each slot
+	free    []int                // Slots available for reuse
+	slots   map[K]int            // Slot of each key
+	heap    *Heap[int]           // Occupied slots ordered by value
+}
+
+// indexedEntry is a value together with its key.
+type indexedEntry[K comparable, T any] struct {
+	key   K
+	value T
+}
+
+// NewIndexedHeap creates an empty indexed heap with branching factor d whose
+// values are ordered by lessFunc.
+func NewIndexedHeap[K comparable, T any](d int, lessFunc func(T, T) bool) *IndexedHeap[K, T] {
+	x := &IndexedHeap[K, T]{slots: make(map[K]int)}
+	x.heap = NewHeap[int](d, func(a, b int) bool {
+		return lessFunc(x.entries[a].value, x.entries[b].value)
+	})
+	return x
+}
+
+// Len returns the number of keys in the heap.
+func (x *IndexedHeap[K, T]) Len() int {
+	return x.heap.heapSize
+}
+
+// Set inserts key with value, or changes the value of key if it is already
+// present, in O(log n).
+func (x *IndexedHeap[K, T]) Set(key K, value T) {
+	if slot, exists := x.slots[key]; exists {
+		x.entries[slot].value = value
+		i, _ := x.heap.find(slot)
+		x.heap.fix(i)
+		return
+	}
+	e := indexedEntry[K, T]{key: key, value: value}
+	var slot int
+	if n := len(x.free); n > 0 {
+		slot = x.free[n-1]
+		x.free = x.free[:n-1]
+		x.entries[slot] = e
+	} else {
+		slot = len(x.entries)
+		x.entries = append(x.entries, e)
+	}
+	x.slots[key] = slot
+	x.heap.Push(slot)
+}
+
+// Get returns the value of key in O(1). If key is not present, it returns the
+// zero value of type T and false.
+func (x *IndexedHeap[K, T]) Get(key K) (T, bool) {
+	slot, exists := x.slots[key]
+	if !exists {
+		var zero T
+		return zero, false
+	}
+	return x.entries[slot].value, true
+}
+
+// Remove removes key and its value in O(log n). It returns false if key is
+// not present.
+func (x *IndexedHeap[K, T]) Remove(key K) bool {
+	slot, exists := x.slots[key]
+	if !exists {
+		return false
+	}
+	i, _ := x.heap.find(slot)
+	x.heap.removeAt(i)
+	x.release(slot)
+	return true
+}
+
+// Peek returns the key and value that order first without removing them. If
+// the heap is empty, it returns zero values and false.
+func (x *IndexedHeap[K, T]) Peek() (K, T, bool) {
+	if x.heap.heapSize == 0 {
+		var e indexedEntry[K, T]
+		return e.key, e.value, false
+	}
+	e := x.entries[x.heap.Peek()]
+	return e.key, e.value, true
+}
+
+// Pop removes and returns the key and value that order first. If the heap is
+// empty, it returns zero values and false.
+func (x *IndexedHeap[K, T]) Pop() (K, T, bool) {
+	if x.heap.heapSize == 0 {
+		var e indexedEntry[K, T]
+		return e.key, e.value, false
+	}
+	slot := x.heap.Pop()
+	e := x.entries[slot]
+	x.release(slot)
+	return e.key, e.value, true
+}
+
+// release forgets the key in slot and makes the slot available for reuse.
+func (x *IndexedHeap[K, T]) release(slot int) {
+	delete(x.slots, x.entries[slot].key)
+	x.entries[slot] = indexedEntry[K, T]{} // Do not retain the value
+	x.free = append(x.free, slot)
+}
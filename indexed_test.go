@@ -0,0 +1,74 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexedHeap(t *testing.T) {
+	t.Parallel()
+
+	type timer struct {
+		deadline int
+		name     string
+	}
+	timers := NewIndexedHeap[string, timer](4, func(a, b timer) bool { return a.deadline < b.deadline })
+	_, _, ok := timers.Pop()
+	assert.False(t, ok)
+
+	timers.Set("a", timer{30, "retry"})
+	timers.Set("b", timer{10, "flush"})
+	timers.Set("c", timer{20, "ping"})
+	timers.Set("b", timer{40, "flush"}) // Postponed
+	assert.Equal(t, 3, timers.Len())
+
+	v, ok := timers.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 40, v.deadline)
+	_, ok = timers.Get("z")
+	assert.False(t, ok)
+
+	assert.True(t, timers.Remove("c"))
+	assert.False(t, timers.Remove("c"))
+	timers.Set("d", timer{5, "gc"}) // Reuses the slot freed by c
+
+	key, _, ok := timers.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, "d", key)
+	for _, want := range []string{"d", "a", "b"} {
+		key, _, ok := timers.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, want, key)
+	}
+	assert.Zero(t, timers.Len())
+	assert.Empty(t, timers.slots)
+}
+
+func TestIndexedHeapDijkstra(t *testing.T) {
+	t.Parallel()
+
+	edges := map[string]map[string]int{
+		"a": {"b": 7, "c": 9, "f": 14},
+		"b": {"c": 10, "d": 15},
+		"c": {"d": 11, "f": 2},
+		"d": {"e": 6},
+		"f": {"e": 9},
+	}
+	dist := map[string]int{}
+	frontier := NewIndexedHeap[string, int](2, func(a, b int) bool { return a < b })
+	frontier.Set("a", 0)
+	for frontier.Len() > 0 {
+		u, du, _ := frontier.Pop()
+		dist[u] = du
+		for v, w := range edges[u] {
+			if _, done := dist[v]; done {
+				continue
+			}
+			if dv, ok := frontier.Get(v); !ok || du+w < dv {
+				frontier.Set(v, du+w)
+			}
+		}
+	}
+	assert.Equal(t, map[string]int{"a": 0, "b": 7, "c": 9, "d": 20, "e": 20, "f": 11}, dist)
+}
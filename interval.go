@@ -0,0 +1,53 @@
+package heap
+
+import (
+	"cmp"
+	"sort"
+)
+
+// Interval is a half-open interval [Start, End).
+type Interval[T cmp.Ordered] struct {
+	Start T
+	End   T
+}
+
+// AssignResources assigns each interval to a resource (a meeting room, a
+// worker, a machine) so that no resource holds two overlapping intervals, using
+// the fewest resources possible. Intervals are half-open, so one that ends at
+// the instant another starts can share its resource.
+//
+// It returns the number of resources used, which is also the maximum number of
+// intervals that overlap at any instant, and the resource assigned to each
+// interval, numbered from zero. It runs in O(n log n) time.
+func AssignResources[T cmp.Ordered](intervals []Interval[T]) (int, []int) {
+	order := make([]int, len(intervals))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return intervals[order[a]].Start < intervals[order[b]].Start })
+
+	var freeAt []T // End of the last interval held by each resource
+	busy := NewHeap[int](2, func(a, b int) bool { return freeAt[a] < freeAt[b] })
+	assigned := make([]int, len(intervals))
+	for _, i := range order {
+		iv := intervals[i]
+		var r int
+		if busy.heapSize > 0 && freeAt[busy.Peek()] <= iv.Start {
+			r = busy.Pop()
+			freeAt[r] = iv.End
+		} else {
+			r = len(freeAt)
+			freeAt = append(freeAt, iv.End)
+		}
+		assigned[i] = r
+		busy.Push(r)
+	}
+	return len(freeAt), assigned
+}
+
+// MaxOverlap returns the maximum number of half-open intervals that overlap at
+// any instant, which is the minimum number of resources needed to serve them.
+func MaxOverlap[T cmp.Ordered](intervals []Interval[T]) int {
+	n, _ := AssignResources(intervals)
+	return n
+}
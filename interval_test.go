@@ -0,0 +1,43 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignResources(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []Interval[int]
+		want      int
+	}{
+		{name: "Empty", want: 0},
+		{name: "Disjoint", intervals: []Interval[int]{{0, 1}, {2, 3}, {4, 5}}, want: 1},
+		{name: "Touching", intervals: []Interval[int]{{0, 5}, {5, 10}, {10, 15}}, want: 1},
+		{name: "Nested", intervals: []Interval[int]{{0, 10}, {1, 9}, {2, 8}}, want: 3},
+		{name: "Meeting rooms", intervals: []Interval[int]{{0, 30}, {5, 10}, {15, 20}, {10, 25}, {25, 35}}, want: 3},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			n, assigned := AssignResources(tt.intervals)
+			assert.Equal(t, tt.want, n)
+			assert.Equal(t, tt.want, MaxOverlap(tt.intervals))
+			assert.Len(t, assigned, len(tt.intervals))
+
+			for i, a := range tt.intervals {
+				assert.True(t, assigned[i] >= 0 && assigned[i] < n, "interval %d assigned to resource %d", i, assigned[i])
+				for j, b := range tt.intervals[i+1:] {
+					j += i + 1
+					if assigned[i] == assigned[j] {
+						assert.False(t, a.Start < b.End && b.Start < a.End, "intervals %v and %v share resource %d", a, b, assigned[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package heap
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// All returns an iterator over the elements in no particular order. The heap
+// must not be modified during iteration.
+func (h *Heap[T]) All() iter.Seq[T] {
+	h.purge()
+	return slices.Values(h.data[:h.heapSize])
+}
+
+// At returns the element at position i of the heap's array, where position 0
+// is the root and the children of position i are at i·d+1 through i·d+d. It
+// panics if i is not in [0, Len()).
+func (h *Heap[T]) At(i int) T {
+	h.ensureHeap()
+	if i < 0 || i >= h.heapSize {
+		panic(fmt.Sprintf("heap: index %d out of range [0, %d)", i, h.heapSize))
+	}
+	return h.data[i]
+}
+
+// Values returns a copy of the elements in array order, so Values()[i] equals
+// At(i). The heap is not modified.
+func (h *Heap[T]) Values() []T {
+	h.ensureHeap()
+	return slices.Clone(h.data[:h.heapSize])
+}
+
+// Ordered returns an iterator over the elements in priority order. It works on
+// a copy of the heap, which it takes when iteration starts, so the heap is not
+// drained and may be modified while iterating. Each element costs
+// O(d log_d n), so stopping early is cheaper than a full sort. Expired elements
+// are left out.
+func (h *Heap[T]) Ordered() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range IncrementalSortFunc(h.live(), h.d, h.lessFunc) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Drain returns an iterator that pops elements in priority order until the
+// heap is empty. Elements are popped lazily as the loop asks for them, so
+// breaking out of the loop leaves the rest in the heap, and elements pushed
+// during iteration are drained too.
+func (h *Heap[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for !h.IsEmpty() {
+			if !yield(h.Pop()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package heap
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapIterators(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b }, WithLazyHeapify[int]())
+	for _, v := range []int{6, 2, 9, 2, 5, 1} {
+		heap.Push(v)
+	}
+
+	all := slices.Collect(heap.All())
+	slices.Sort(all)
+	assert.Equal(t, []int{1, 2, 2, 5, 6, 9}, all)
+
+	assert.Equal(t, []int{1, 2, 2, 5, 6, 9}, slices.Collect(heap.Ordered()))
+
+	var first []int
+	for v := range heap.Ordered() {
+		if len(first) == 2 {
+			break
+		}
+		first = append(first, v)
+		heap.Push(0) // Mutations do not affect the iteration in progress
+	}
+	assert.Equal(t, []int{1, 2}, first)
+	assert.Equal(t, 8, heap.Len())
+}
+
+func TestHeapDrain(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a > b })
+	for _, v := range []int{4, 8, 1, 6} {
+		heap.Push(v)
+	}
+
+	var got []int
+	for v := range heap.Drain() {
+		got = append(got, v)
+		if v == 6 {
+			break
+		}
+	}
+	assert.Equal(t, []int{8, 6}, got)
+	assert.Equal(t, 2, heap.Len(), "break did not stop draining")
+
+	heap.Push(5)
+	assert.Equal(t, []int{5, 4, 1}, slices.Collect(heap.Drain()))
+	assert.True(t, heap.IsEmpty())
+	assert.Empty(t, heap.index)
+}
+
+func TestHeapAtValues(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b }, WithLazyHeapify[int]())
+	for _, v := range []int{5, 3, 8, 1, 4} {
+		heap.Push(v)
+	}
+
+	values := heap.Values()
+	assert.Len(t, values, 5)
+	assert.Equal(t, 1, heap.At(0), "At restores deferred order")
+	for i, v := range values {
+		assert.Equal(t, v, heap.At(i))
+		if i > 0 {
+			assert.LessOrEqual(t, heap.At((i-1)/2), v)
+		}
+	}
+
+	values[0] = 100
+	assert.Equal(t, 1, heap.Peek(), "Values returned the backing array")
+	heap.Pop()
+	assert.Panics(t, func() { heap.At(4) })
+	assert.Panics(t, func() { heap.At(-1) })
+}
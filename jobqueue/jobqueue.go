@@ -0,0 +1,331 @@
+// Package jobqueue provides a work queue built on the d-ary heap with
+// SQS-style delivery semantics. Popping a job hands it out and hides it for a
+// visibility window instead of removing it; a job that is not acknowledged
+// within the window automatically returns to the queue at its original
+// priority, so work held by a crashed worker is not lost.
+//
+// Workers confirm completion with Ack, or hand a job back with Nack, which
+// makes it ready again after an optional backoff delay. Both take the Receipt
+// of a single delivery, so a worker whose window expired cannot complete or
+// hand back the job once it has been redelivered to someone else. Long-running work can
+// be taken under a renewable Lease instead of a fixed visibility window. With
+// WithMaxAttempts, a job that keeps failing is moved to a dead-letter heap
+// instead of cycling through the queue forever.
+package jobqueue
+
+import (
+	"sync"
+	"time"
+
+	heap "github.com/ahrav/go-d-ary-heap"
+)
+
+// Handle identifies a job for its whole life in the queue.
+type Handle uint64
+
+// Receipt identifies one delivery of a job, so that only the worker holding
+// the current delivery can acknowledge it or hand it back.
+type Receipt struct {
+	Handle  Handle // Job delivered
+	Attempt int    // Delivery number, counting from 1
+}
+
+// Job is a unit of work handed out by Pop.
+type Job[V any, P comparable] struct {
+	Handle   Handle    // Identifies the job across deliveries
+	Value    V         // Payload supplied to Push
+	Priority P         // Priority supplied to Push
+	Deadline time.Time // Time at which the job becomes visible again unless acknowledged
+	Attempts int       // Number of times the job has been handed out, including this one
+}
+
+// state is the delivery state of a job.
+type state int
+
+const (
+	ready    state = iota // Waiting to be popped
+	inFlight              // Handed out and hidden until its deadline
+	delayed               // Handed back and hidden until its deadline
+)
+
+// entry is the queue's record of a job.
+type entry[V any, P comparable] struct {
+	value    V
+	priority P
+	state    state
+	deadline int64 // Time it becomes ready in Unix nanoseconds while in flight or delayed
+	attempts int   // Number of deliveries
+}
+
+// Receipt returns the receipt for this delivery of the job, to pass to Ack or
+// Nack.
+func (j Job[V, P]) Receipt() Receipt {
+	return Receipt{Handle: j.Handle, Attempt: j.Attempts}
+}
+
+// Option is a type representing configurations for the queue.
+type Option[V any, P comparable] func(*Queue[V, P])
+
+// WithClock is an option that sets the clock used for visibility deadlines.
+func WithClock[V any, P comparable](clock heap.Clock) Option[V, P] {
+	return func(q *Queue[V, P]) {
+		q.clock = clock
+	}
+}
+
+// WithMaxAttempts is an option that dead-letters a job once it has been
+// delivered n times without being acknowledged. A delivery fails when the job
+// is handed back with Nack or its visibility window expires.
+func WithMaxAttempts[V any, P comparable](n int) Option[V, P] {
+	return func(q *Queue[V, P]) {
+		q.maxAttempts = n
+	}
+}
+
+// WithDeadLetterFunc is an option that passes dead-lettered jobs to fn instead
+// of keeping them in the dead-letter heap. fn is called with the queue locked
+// and must not call back into the queue.
+func WithDeadLetterFunc[V any, P comparable](fn func(Job[V, P])) Option[V, P] {
+	return func(q *Queue[V, P]) {
+		q.onDead = fn
+	}
+}
+
+// Queue is a priority work queue with visibility timeouts. It is safe for use
+// by multiple goroutines.
+type Queue[V any, P comparable] struct {
+	mu         sync.Mutex
+	clock      heap.Clock
+	visibility time.Duration
+	jobs       map[Handle]*entry[V, P]
+	ready      *heap.PriorityQueue[Handle, P]     // Jobs waiting to be popped
+	timers     *heap.PriorityQueue[Handle, int64] // Deadlines of jobs, possibly stale
+	next       Handle
+	inFlight   int // Number of jobs in flight
+	delayed    int // Number of jobs waiting out a Nack delay
+
+	maxAttempts int                               // Deliveries before dead-lettering, zero for no limit
+	onDead      func(Job[V, P])                   // Receives dead-lettered jobs if set
+	dead        *heap.PriorityQueue[Job[V, P], P] // Dead-lettered jobs
+}
+
+// New creates an empty queue whose priorities are ordered by less and whose
+// popped jobs stay hidden for visibility unless acknowledged.
+func New[V any, P comparable](less func(P, P) bool, visibility time.Duration, options ...Option[V, P]) *Queue[V, P] {
+	q := &Queue[V, P]{
+		clock:      heap.SystemClock{},
+		visibility: visibility,
+		jobs:       make(map[Handle]*entry[V, P]),
+		ready:      heap.NewPriorityQueue[Handle, P](4, less),
+		timers:     heap.NewPriorityQueue[Handle, int64](4, func(a, b int64) bool { return a < b }),
+		dead:       heap.NewPriorityQueue[Job[V, P]](4, less),
+	}
+	for _, option := range options {
+		option(q)
+	}
+	return q
+}
+
+// Push adds a job and returns its handle.
+func (q *Queue[V, P]) Push(value V, priority P) Handle {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.next++
+	q.jobs[q.next] = &entry[V, P]{value: value, priority: priority}
+	q.ready.Push(q.next, priority)
+	return q.next
+}
+
+// Pop hands out the extremal ready job and hides it until its deadline. If no
+// job is ready, it returns the zero Job and false.
+func (q *Queue[V, P]) Pop() (Job[V, P], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.deliver(q.clock.Now(), q.visibility)
+}
+
+// deliver hands out the extremal ready job and hides it for window.
+func (q *Queue[V, P]) deliver(now time.Time, window time.Duration) (Job[V, P], bool) {
+	q.reclaim(now)
+	if q.ready.Len() == 0 {
+		return Job[V, P]{}, false
+	}
+	h, _ := q.ready.Pop()
+	e := q.jobs[h]
+	e.state, e.deadline = inFlight, now.Add(window).UnixNano()
+	e.attempts++
+	q.timers.Push(h, e.deadline)
+	q.inFlight++
+	return e.job(h), true
+}
+
+// Ack acknowledges that the delivery r has been processed and removes its job
+// from the queue. It returns false if the delivery is no longer in flight, for
+// example because its visibility window expired, even if the job has since
+// been delivered again.
+func (q *Queue[V, P]) Ack(r Receipt) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.delivered(q.clock.Now(), r); !ok {
+		return false
+	}
+	delete(q.jobs, r.Handle) // Its timer entry is now stale and skipped by reclaim
+	q.inFlight--
+	return true
+}
+
+// Nack hands the job of delivery r back to the queue without completing it.
+// The job becomes ready again at its original priority once delay has
+// elapsed, or immediately if delay is not positive. It returns false if the
+// delivery is no longer in flight.
+func (q *Queue[V, P]) Nack(r Receipt, delay time.Duration) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.clock.Now()
+	e, ok := q.delivered(now, r)
+	if !ok {
+		return false
+	}
+	q.requeue(now, r.Handle, e, delay)
+	return true
+}
+
+// delivered returns the entry of the job delivered under r if that delivery
+// is still in flight at now. The delivery count tells r apart from later
+// deliveries of the same job.
+func (q *Queue[V, P]) delivered(now time.Time, r Receipt) (*entry[V, P], bool) {
+	q.reclaim(now)
+	e, exists := q.jobs[r.Handle]
+	if !exists || e.state != inFlight || e.attempts != r.Attempt {
+		return nil, false
+	}
+	return e, true
+}
+
+// requeue hands the in-flight job h back, dead-lettering it if it has used
+// up its deliveries.
+func (q *Queue[V, P]) requeue(now time.Time, h Handle, e *entry[V, P], delay time.Duration) {
+	q.inFlight--
+	switch {
+	case q.exhausted(e):
+		q.deadLetter(h, e)
+	case delay <= 0:
+		e.state = ready
+		q.ready.Push(h, e.priority)
+	default:
+		e.state, e.deadline = delayed, now.Add(delay).UnixNano()
+		q.timers.Push(h, e.deadline)
+		q.delayed++
+	}
+}
+
+// Len returns the number of jobs ready to be popped.
+func (q *Queue[V, P]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reclaim(q.clock.Now())
+	return q.ready.Len()
+}
+
+// InFlight returns the number of jobs handed out and not yet acknowledged.
+func (q *Queue[V, P]) InFlight() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reclaim(q.clock.Now())
+	return q.inFlight
+}
+
+// Delayed returns the number of jobs handed back by Nack that are waiting out
+// their delay.
+func (q *Queue[V, P]) Delayed() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reclaim(q.clock.Now())
+	return q.delayed
+}
+
+// DeadLetters returns the number of jobs in the dead-letter heap.
+func (q *Queue[V, P]) DeadLetters() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reclaim(q.clock.Now())
+	return q.dead.Len()
+}
+
+// PopDeadLetter removes and returns the extremal job from the dead-letter
+// heap. If the heap is empty, it returns the zero Job and false.
+func (q *Queue[V, P]) PopDeadLetter() (Job[V, P], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reclaim(q.clock.Now())
+	if q.dead.Len() == 0 {
+		return Job[V, P]{}, false
+	}
+	job, _ := q.dead.Pop()
+	return job, true
+}
+
+// exhausted reports whether e has used up its deliveries.
+func (q *Queue[V, P]) exhausted(e *entry[V, P]) bool {
+	return q.maxAttempts > 0 && e.attempts >= q.maxAttempts
+}
+
+// deadLetter removes job h from the queue and hands it to the dead-letter
+// function or heap.
+func (q *Queue[V, P]) deadLetter(h Handle, e *entry[V, P]) {
+	delete(q.jobs, h)
+	job := e.job(h)
+	if q.onDead != nil {
+		q.onDead(job)
+		return
+	}
+	q.dead.Push(job, job.Priority)
+}
+
+// job describes e as the Job with handle h.
+func (e *entry[V, P]) job(h Handle) Job[V, P] {
+	return Job[V, P]{
+		Handle:   h,
+		Value:    e.value,
+		Priority: e.priority,
+		Deadline: time.Unix(0, e.deadline),
+		Attempts: e.attempts,
+	}
+}
+
+// reclaim returns every in-flight or delayed job whose deadline has passed to
+// the ready heap at its original priority. Timer entries for jobs that were
+// acknowledged, handed back or handed out again since are skipped.
+func (q *Queue[V, P]) reclaim(now time.Time) {
+	for q.timers.Len() > 0 {
+		h, deadline, _ := q.timers.Peek()
+		if deadline > now.UnixNano() {
+			return
+		}
+		q.timers.Pop()
+		e, exists := q.jobs[h]
+		if !exists || e.state == ready || e.deadline != deadline {
+			continue
+		}
+		if e.state == inFlight {
+			q.inFlight--
+			if q.exhausted(e) {
+				q.deadLetter(h, e)
+				continue
+			}
+		} else {
+			q.delayed--
+		}
+		e.state = ready
+		q.ready.Push(h, e.priority)
+	}
+}
@@ -0,0 +1,166 @@
+package jobqueue
+
+import (
+	"testing"
+	"time"
+
+	heap "github.com/ahrav/go-d-ary-heap"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestQueue(visibility time.Duration) (*Queue[string, int], *heap.EventClock, time.Time) {
+	start := time.Unix(0, 0)
+	clock := heap.NewVirtualClock(start)
+	q := New[string, int](func(a, b int) bool { return a < b }, visibility, WithClock[string, int](clock))
+	return q, clock, start
+}
+
+func TestVisibilityTimeout(t *testing.T) {
+	q, clock, start := newTestQueue(30 * time.Second)
+	q.Push("low", 5)
+	q.Push("high", 1)
+
+	job, ok := q.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, "high", job.Value)
+	assert.Equal(t, start.Add(30*time.Second), job.Deadline)
+	assert.Equal(t, 1, q.Len())
+	assert.Equal(t, 1, q.InFlight())
+
+	// The unacknowledged job reappears at its original priority.
+	clock.RunUntil(start.Add(30 * time.Second))
+	assert.Equal(t, 2, q.Len())
+	assert.Zero(t, q.InFlight())
+	assert.False(t, q.Ack(job.Receipt()), "Ack succeeded after the visibility window expired")
+
+	again, ok := q.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, job.Handle, again.Handle)
+	assert.Equal(t, "high", again.Value)
+}
+
+func TestAckRemovesJob(t *testing.T) {
+	q, clock, start := newTestQueue(10 * time.Second)
+	h := q.Push("work", 1)
+
+	job, _ := q.Pop()
+	assert.Equal(t, h, job.Handle)
+	assert.True(t, q.Ack(job.Receipt()))
+	assert.False(t, q.Ack(job.Receipt()), "job acknowledged twice")
+
+	clock.RunUntil(start.Add(time.Minute))
+	assert.Zero(t, q.Len())
+	assert.Zero(t, q.InFlight())
+	_, ok := q.Pop()
+	assert.False(t, ok)
+}
+
+func TestRedeliveredJobGetsNewDeadline(t *testing.T) {
+	q, clock, start := newTestQueue(10 * time.Second)
+	q.Push("work", 1)
+
+	first, _ := q.Pop()
+	clock.RunUntil(start.Add(10 * time.Second))
+	job, _ := q.Pop()
+	assert.Equal(t, start.Add(20*time.Second), job.Deadline)
+
+	// The stale timer from the first delivery does not reclaim the job early.
+	clock.RunUntil(start.Add(15 * time.Second))
+	assert.Equal(t, 1, q.InFlight())
+	assert.False(t, q.Ack(first.Receipt()), "stale receipt acknowledged the redelivery")
+	assert.True(t, q.Ack(job.Receipt()))
+}
+
+func TestNack(t *testing.T) {
+	q, clock, start := newTestQueue(time.Minute)
+	h := q.Push("flaky", 1)
+	q.Push("other", 2)
+
+	job, _ := q.Pop()
+	assert.Equal(t, h, job.Handle)
+	assert.True(t, q.Nack(job.Receipt(), 5*time.Second))
+	assert.False(t, q.Nack(job.Receipt(), 0), "Nack of a job that is not in flight succeeded")
+	assert.Zero(t, q.InFlight())
+	assert.Equal(t, 1, q.Delayed())
+
+	// While backing off the job is hidden, so the other job is served.
+	job, _ = q.Pop()
+	assert.Equal(t, "other", job.Value)
+	assert.True(t, q.Ack(job.Receipt()))
+
+	clock.RunUntil(start.Add(5 * time.Second))
+	assert.Zero(t, q.Delayed())
+	job, ok := q.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, h, job.Handle)
+
+	// Nack without delay makes the job ready at once.
+	assert.True(t, q.Nack(job.Receipt(), 0))
+	assert.Equal(t, 1, q.Len())
+	job, _ = q.Pop()
+	assert.True(t, q.Ack(job.Receipt()))
+	assert.Zero(t, q.Len()+q.InFlight()+q.Delayed())
+}
+
+func TestDeadLetterAfterMaxAttempts(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := heap.NewVirtualClock(start)
+	q := New[string, int](func(a, b int) bool { return a < b }, 10*time.Second,
+		WithClock[string, int](clock), WithMaxAttempts[string, int](3))
+	h := q.Push("poison", 1)
+
+	// Two failures by Nack and one by visibility timeout.
+	for attempt := 1; attempt <= 2; attempt++ {
+		job, _ := q.Pop()
+		assert.Equal(t, attempt, job.Attempts)
+		assert.True(t, q.Nack(job.Receipt(), 0))
+	}
+	job, _ := q.Pop()
+	assert.Equal(t, 3, job.Attempts)
+	clock.RunUntil(start.Add(10 * time.Second))
+
+	assert.Zero(t, q.Len())
+	assert.Zero(t, q.InFlight())
+	assert.Equal(t, 1, q.DeadLetters())
+
+	dead, ok := q.PopDeadLetter()
+	assert.True(t, ok)
+	assert.Equal(t, h, dead.Handle)
+	assert.Equal(t, "poison", dead.Value)
+	assert.Equal(t, 3, dead.Attempts)
+	_, ok = q.PopDeadLetter()
+	assert.False(t, ok)
+}
+
+func TestDeadLetterFunc(t *testing.T) {
+	var dead []Job[string, int]
+	q := New[string, int](func(a, b int) bool { return a < b }, time.Minute,
+		WithMaxAttempts[string, int](1),
+		WithDeadLetterFunc[string, int](func(job Job[string, int]) { dead = append(dead, job) }))
+	q.Push("once", 1)
+
+	job, _ := q.Pop()
+	assert.True(t, q.Nack(job.Receipt(), time.Second))
+	assert.Len(t, dead, 1)
+	assert.Equal(t, "once", dead[0].Value)
+	assert.Zero(t, q.DeadLetters())
+	assert.Zero(t, q.Delayed())
+}
+
+func TestStaleReceiptRejected(t *testing.T) {
+	q, clock, start := newTestQueue(10 * time.Second)
+	q.Push("work", 1)
+
+	slow, _ := q.Pop()
+	clock.RunUntil(start.Add(10 * time.Second))
+	fresh, ok := q.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, slow.Handle, fresh.Handle)
+
+	// The slow worker's delivery lapsed; it must not touch the redelivery.
+	assert.False(t, q.Nack(slow.Receipt(), 0))
+	assert.False(t, q.Ack(slow.Receipt()))
+	assert.Equal(t, 1, q.InFlight())
+	assert.True(t, q.Ack(fresh.Receipt()))
+	assert.Zero(t, q.InFlight())
+}
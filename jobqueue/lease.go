@@ -0,0 +1,78 @@
+package jobqueue
+
+import "time"
+
+// Lease is a claim on an in-flight job for long-running work. The worker must
+// renew the lease before it expires; once it lapses the job returns to the
+// queue and the lease can no longer be renewed, acknowledged or handed back,
+// even if the same job has since been delivered to another worker.
+type Lease[V any, P comparable] struct {
+	Job[V, P]
+	queue *Queue[V, P]
+}
+
+// PopLease hands out the extremal ready job under a lease lasting ttl. If no
+// job is ready, it returns nil and false.
+func (q *Queue[V, P]) PopLease(ttl time.Duration) (*Lease[V, P], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.deliver(q.clock.Now(), ttl)
+	if !ok {
+		return nil, false
+	}
+	return &Lease[V, P]{Job: job, queue: q}, true
+}
+
+// Expiry returns the time at which the lease lapses unless renewed.
+func (l *Lease[V, P]) Expiry() time.Time {
+	return l.Deadline
+}
+
+// Renew extends the lease to ttl from now. It returns false if the lease has
+// already lapsed.
+func (l *Lease[V, P]) Renew(ttl time.Duration) bool {
+	q := l.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.clock.Now()
+	e, ok := q.delivered(now, l.Receipt())
+	if !ok {
+		return false
+	}
+	e.deadline = now.Add(ttl).UnixNano()
+	q.timers.Push(l.Handle, e.deadline) // The previous timer entry is now stale
+	l.Deadline = time.Unix(0, e.deadline)
+	return true
+}
+
+// Ack completes the leased job. It returns false if the lease has lapsed.
+func (l *Lease[V, P]) Ack() bool {
+	q := l.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.delivered(q.clock.Now(), l.Receipt()); !ok {
+		return false
+	}
+	delete(q.jobs, l.Handle)
+	q.inFlight--
+	return true
+}
+
+// Nack hands the leased job back like Queue.Nack. It returns false if the
+// lease has lapsed.
+func (l *Lease[V, P]) Nack(delay time.Duration) bool {
+	q := l.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.clock.Now()
+	e, ok := q.delivered(now, l.Receipt())
+	if !ok {
+		return false
+	}
+	q.requeue(now, l.Handle, e, delay)
+	return true
+}
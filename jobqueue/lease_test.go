@@ -0,0 +1,57 @@
+package jobqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaseRenewal(t *testing.T) {
+	q, clock, start := newTestQueue(time.Minute)
+	q.Push("long job", 1)
+
+	lease, ok := q.PopLease(10 * time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(10*time.Second), lease.Expiry())
+
+	// Renewing before expiry keeps the job in flight past the original expiry.
+	clock.RunUntil(start.Add(8 * time.Second))
+	assert.True(t, lease.Renew(10*time.Second))
+	assert.Equal(t, start.Add(18*time.Second), lease.Expiry())
+	clock.RunUntil(start.Add(15 * time.Second))
+	assert.Equal(t, 1, q.InFlight())
+	assert.Zero(t, q.Len())
+
+	assert.True(t, lease.Ack())
+	assert.Zero(t, q.InFlight())
+	assert.False(t, lease.Renew(time.Second), "renewed an acknowledged lease")
+}
+
+func TestExpiredLeaseRequeues(t *testing.T) {
+	q, clock, start := newTestQueue(time.Minute)
+	q.Push("job", 1)
+
+	stale, _ := q.PopLease(10 * time.Second)
+	clock.RunUntil(start.Add(10 * time.Second))
+	assert.Equal(t, 1, q.Len(), "expired lease did not requeue the job")
+	assert.False(t, stale.Renew(10*time.Second))
+
+	// A second worker takes the job; the stale lease cannot touch it.
+	fresh, ok := q.PopLease(10 * time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, stale.Handle, fresh.Handle)
+	assert.Equal(t, 2, fresh.Attempts)
+	assert.False(t, stale.Ack())
+	assert.False(t, stale.Nack(0))
+
+	assert.True(t, fresh.Nack(0))
+	assert.Equal(t, 1, q.Len())
+}
+
+func TestPopLeaseEmpty(t *testing.T) {
+	q, _, _ := newTestQueue(time.Minute)
+	lease, ok := q.PopLease(time.Second)
+	assert.False(t, ok)
+	assert.Nil(t, lease)
+}
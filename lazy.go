@@ -0,0 +1,40 @@
+package heap
+
+// WithLazyHeapify is an option that defers restoring the heap property after
+// pushes. Push only appends the element and marks the heap dirty; the first
+// operation that depends on the order, such as Peek or Pop, restores it with a
+// single O(n) pass. Workloads that ingest a burst of elements and then drain
+// them do O(n) work for the burst instead of O(n log n).
+func WithLazyHeapify[T comparable]() Option[T] {
+	return func(h *Heap[T]) {
+		h.lazy = true
+	}
+}
+
+// heapify restores the heap property over the whole heap in O(n) time.
+func (h *Heap[T]) heapify() {
+	if h.heapSize < 2 {
+		return
+	}
+	for i := h.parent(h.heapSize - 1); i >= 0; i-- {
+		h.down(i)
+	}
+}
+
+// ensureHeap restores the heap property if pushes have been deferred and
+// purges elements deleted by LazyRemove.
+func (h *Heap[T]) ensureHeap() {
+	h.ensureOrder()
+	h.purge()
+}
+
+// ensureOrder restores the heap property if pushes have been deferred.
+func (h *Heap[T]) ensureOrder() {
+	if h.dirty {
+		h.dirty = false
+		h.staged = 0
+		h.heapify()
+		return
+	}
+	h.mergeStaged()
+}
@@ -0,0 +1,37 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyHeapify(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b }, WithLazyHeapify[int]())
+
+	var values []int
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 100; i++ {
+			v := rng.Intn(1000)
+			values = append(values, v)
+			heap.Push(v)
+		}
+		assert.True(t, heap.dirty, "Push restored the heap property eagerly")
+
+		sort.Ints(values)
+		assert.Equal(t, values[0], heap.Peek())
+		assert.False(t, heap.dirty, "Peek did not restore the heap property")
+
+		for i := 0; i < 50; i++ {
+			assert.Equal(t, values[0], heap.Pop())
+			values = values[1:]
+		}
+	}
+
+	heap.Push(-1)
+	assert.True(t, heap.Contains(-1))
+	assert.Equal(t, 1, heap.CountLess(values[0]), "CountLess did not restore the heap property")
+}
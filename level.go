@@ -0,0 +1,151 @@
+package heap
+
+// Queue is the payload-and-priority API shared by PriorityQueue, LevelQueue
+// and MLQueue, so code can switch between them.
+type Queue[V any, P comparable] interface {
+	Push(value V, priority P)
+	Pop() (V, P)
+	Peek() (V, P, bool)
+	Len() int
+}
+
+var (
+	_ Queue[int, int] = (*PriorityQueue[int, int])(nil)
+	_ Queue[int, int] = (*LevelQueue[int, int])(nil)
+	_ Queue[int, int] = (*MLQueue[int])(nil)
+)
+
+// LevelQueue is a priority queue for priorities drawn from a small discrete
+// set. It keeps a FIFO queue per priority level and a heap of the levels that
+// are non-empty, so Push and Pop cost O(log L) for L distinct levels rather
+// than O(log n) for n elements, and elements of equal priority pop in the
+// order they were pushed.
+type LevelQueue[V any, P comparable] struct {
+	levels map[P]*fifo[V] // Queued payloads of each non-empty level
+	order  *Heap[P]       // Non-empty levels
+	spare  []*fifo[V]     // Emptied queues kept for reuse
+	size   int
+
+	fairness *fairnessTracker[P] // Per-class wait tracking, nil unless enabled
+	weights  []float64           // Dequeue weight of each tier, nil for strict order
+	random   func() float64      // Source of randomness for weighted dequeue
+}
+
+// NewLevelQueue creates an empty level queue with branching factor d whose
+// priority levels are ordered by less.
+func NewLevelQueue[V any, P comparable](d int, less func(P, P) bool, options ...LevelOption[V, P]) *LevelQueue[V, P] {
+	q := &LevelQueue[V, P]{
+		levels: make(map[P]*fifo[V]),
+		order:  NewHeap(d, less),
+	}
+	for _, option := range options {
+		option(q)
+	}
+	return q
+}
+
+// Len returns the number of elements in the queue.
+func (q *LevelQueue[V, P]) Len() int {
+	return q.size
+}
+
+// Levels returns the number of distinct non-empty priority levels.
+func (q *LevelQueue[V, P]) Levels() int {
+	return len(q.levels)
+}
+
+// Push adds value at the given priority level.
+func (q *LevelQueue[V, P]) Push(value V, priority P) {
+	level, exists := q.levels[priority]
+	if !exists {
+		if n := len(q.spare); n > 0 {
+			level, q.spare = q.spare[n-1], q.spare[:n-1]
+		} else {
+			level = &fifo[V]{}
+		}
+		q.levels[priority] = level
+		q.order.Push(priority)
+	}
+	level.push(value)
+	q.size++
+	if q.fairness != nil {
+		q.fairness.pushed(priority)
+	}
+}
+
+// Peek returns the oldest payload of the extremal level and its priority
+// without removing it. If the queue is empty, it returns zero values and false.
+func (q *LevelQueue[V, P]) Peek() (V, P, bool) {
+	if q.size == 0 {
+		var value V
+		var priority P
+		return value, priority, false
+	}
+	priority := q.order.Peek()
+	return q.levels[priority].front(), priority, true
+}
+
+// Pop removes and returns the oldest payload of the extremal level and its
+// priority. If the queue was created with WithWeightedDequeue, the level is
+// instead chosen at random by tier weight. If the queue is empty, it returns
+// zero values.
+func (q *LevelQueue[V, P]) Pop() (V, P) {
+	if q.size == 0 {
+		var value V
+		var priority P
+		return value, priority
+	}
+	priority := q.order.Peek()
+	if q.weights != nil {
+		if tier := q.pickTier(); tier > 0 {
+			priority, _ = q.order.KthSmallest(tier + 1)
+		}
+	}
+	return q.popLevel(priority), priority
+}
+
+// popLevel removes and returns the oldest payload of the given non-empty level.
+func (q *LevelQueue[V, P]) popLevel(priority P) V {
+	level := q.levels[priority]
+	value := level.pop()
+	q.size--
+	if q.fairness != nil {
+		q.fairness.popped(priority)
+	}
+	if level.len() == 0 {
+		i, _ := q.order.find(priority)
+		q.order.removeAt(i)
+		delete(q.levels, priority)
+		q.spare = append(q.spare, level)
+	}
+	return value
+}
+
+// fifo is a first-in first-out queue backed by a slice.
+type fifo[V any] struct {
+	items []V
+	head  int
+}
+
+func (f *fifo[V]) len() int { return len(f.items) - f.head }
+
+func (f *fifo[V]) push(v V) { f.items = append(f.items, v) }
+
+func (f *fifo[V]) front() V { return f.items[f.head] }
+
+// pop removes and returns the oldest item, compacting the backing slice once
+// more than half of it has been consumed.
+func (f *fifo[V]) pop() V {
+	var zero V
+	v := f.items[f.head]
+	f.items[f.head] = zero
+	f.head++
+	if f.head == len(f.items) {
+		f.items, f.head = f.items[:0], 0
+	} else if f.head > len(f.items)/2 {
+		n := copy(f.items, f.items[f.head:])
+		clear(f.items[n:])
+		f.items, f.head = f.items[:n], 0
+	}
+	return v
+}
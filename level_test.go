@@ -0,0 +1,72 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelQueue(t *testing.T) {
+	q := NewLevelQueue[string, int](2, func(a, b int) bool { return a < b })
+
+	_, _, ok := q.Peek()
+	assert.False(t, ok)
+
+	q.Push("batch-1", 2)
+	q.Push("urgent-1", 0)
+	q.Push("batch-2", 2)
+	q.Push("normal-1", 1)
+	q.Push("urgent-2", 0)
+	assert.Equal(t, 5, q.Len())
+	assert.Equal(t, 3, q.Levels())
+
+	v, p, ok := q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, "urgent-1", v)
+	assert.Equal(t, 0, p)
+
+	var got []string
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		got = append(got, v)
+	}
+	assert.Equal(t, []string{"urgent-1", "urgent-2", "normal-1", "batch-1", "batch-2"}, got)
+	assert.Zero(t, q.Levels())
+
+	v, p = q.Pop()
+	assert.Zero(t, v)
+	assert.Zero(t, p)
+}
+
+func TestLevelQueueMatchesPriorityQueue(t *testing.T) {
+	less := func(a, b int) bool { return a > b }
+	queues := []Queue[int, int]{NewPriorityQueue[int, int](4, less), NewLevelQueue[int, int](4, less)}
+	for _, q := range queues {
+		for i := 0; i < 300; i++ {
+			q.Push(i, i%7)
+			if i%3 == 0 {
+				q.Pop()
+			}
+		}
+	}
+
+	for queues[0].Len() > 0 {
+		_, p1 := queues[0].Pop()
+		_, p2 := queues[1].Pop()
+		assert.Equal(t, p1, p2)
+	}
+	assert.Zero(t, queues[1].Len())
+}
+
+func TestFIFOCompaction(t *testing.T) {
+	var f fifo[int]
+	for i := 0; i < 10; i++ {
+		f.push(i)
+	}
+	for i := 0; i < 6; i++ {
+		assert.Equal(t, i, f.pop())
+	}
+	assert.Equal(t, 4, f.len())
+	assert.Zero(t, f.head, "fifo did not compact")
+	assert.Equal(t, 6, f.front())
+}
@@ -0,0 +1,67 @@
+package heap
+
+// MedianTracker maintains the median of a stream of values. It keeps the
+// lower half of the values in a max-heap and the upper half in a min-heap, so
+// Add costs O(log n) and the median is read in O(1).
+type MedianTracker[T comparable] struct {
+	less  func(T, T) bool
+	lower *Heap[T] // Lower half, extremal element is its largest
+	upper *Heap[T] // Upper half, extremal element is its smallest
+}
+
+// NewMedianTracker creates an empty median tracker whose two heaps have
+// branching factor d and which orders values by less.
+func NewMedianTracker[T comparable](d int, less func(T, T) bool) *MedianTracker[T] {
+	return &MedianTracker[T]{
+		less:  less,
+		lower: NewHeap(d, func(a, b T) bool { return less(b, a) }, WithoutIndex[T]()),
+		upper: NewHeap(d, less, WithoutIndex[T]()),
+	}
+}
+
+// Len returns the number of values added.
+func (m *MedianTracker[T]) Len() int {
+	return m.lower.heapSize + m.upper.heapSize
+}
+
+// Add adds v to the stream.
+func (m *MedianTracker[T]) Add(v T) {
+	if m.lower.heapSize == 0 || !m.less(m.lower.Peek(), v) {
+		m.lower.Push(v)
+	} else {
+		m.upper.Push(v)
+	}
+	// Keep the lower half equal in size to the upper half or one larger.
+	switch {
+	case m.lower.heapSize > m.upper.heapSize+1:
+		m.upper.Push(m.lower.Pop())
+	case m.upper.heapSize > m.lower.heapSize:
+		m.lower.Push(m.upper.Pop())
+	}
+}
+
+// Median returns the median value, or the lower of the two middle values when
+// an even number of values has been added. If no value has been added, it
+// returns the zero value of type T and false.
+func (m *MedianTracker[T]) Median() (T, bool) {
+	if m.lower.heapSize == 0 {
+		var zero T
+		return zero, false
+	}
+	return m.lower.Peek(), true
+}
+
+// Medians returns the two middle values, which are the same value when an odd
+// number of values has been added. Numeric callers can average them for the
+// conventional median of an even-sized stream. If no value has been added, it
+// returns zero values and false.
+func (m *MedianTracker[T]) Medians() (lo, hi T, ok bool) {
+	if m.lower.heapSize == 0 {
+		return lo, hi, false
+	}
+	lo = m.lower.Peek()
+	if m.upper.heapSize == m.lower.heapSize {
+		return lo, m.upper.Peek(), true
+	}
+	return lo, lo, true
+}
@@ -0,0 +1,38 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMedianTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewMedianTracker[int](4, func(a, b int) bool { return a < b })
+	_, ok := tracker.Median()
+	assert.False(t, ok)
+	_, _, ok = tracker.Medians()
+	assert.False(t, ok)
+
+	rng := rand.New(rand.NewSource(4))
+	var seen []int
+	for i := 0; i < 500; i++ {
+		v := rng.Intn(100)
+		tracker.Add(v)
+		seen = append(seen, v)
+
+		sorted := append([]int(nil), seen...)
+		sort.Ints(sorted)
+		n := len(sorted)
+		median, ok := tracker.Median()
+		assert.True(t, ok)
+		assert.Equal(t, sorted[(n-1)/2], median, "after %d values", n)
+		lo, hi, _ := tracker.Medians()
+		assert.Equal(t, sorted[(n-1)/2], lo)
+		assert.Equal(t, sorted[n/2], hi)
+	}
+	assert.Equal(t, 500, tracker.Len())
+}
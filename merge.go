@@ -0,0 +1,90 @@
+package heap
+
+import "iter"
+
+// mergeArity is the branching factor of the heap of inputs used by the merge
+// functions. Merges rarely have enough inputs for the choice to matter, and 4
+// keeps the heap shallow without making sifts compare many children.
+const mergeArity = 4
+
+// MergeSorted returns an iterator over the elements of seqs, each of which
+// must be sorted under less, as one sequence sorted under less. Elements that
+// compare equal are yielded in the order of the inputs they came from, so the
+// merge is stable. Each element costs O(log k) comparisons for k inputs, and
+// inputs are only advanced as the merged sequence is consumed.
+func MergeSorted[T any](less func(T, T) bool, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		heads := make([]T, len(seqs))
+		nexts := make([]func() (T, bool), len(seqs))
+		inputs := NewHeap[int](mergeArity, mergeLess(less, heads), WithoutIndex[int]())
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			nexts[i] = next
+			if v, ok := next(); ok {
+				heads[i] = v
+				inputs.Push(i)
+			}
+		}
+
+		for !inputs.IsEmpty() {
+			i := inputs.Peek()
+			if !yield(heads[i]) {
+				return
+			}
+			if v, ok := nexts[i](); ok {
+				heads[i] = v
+				inputs.Replace(i) // Re-sift the input under its new head
+			} else {
+				inputs.Pop()
+			}
+		}
+	}
+}
+
+// MergeSortedSlices merges slices, each of which must be sorted under less,
+// into a new slice sorted under less. Like MergeSorted the merge is stable,
+// and it runs in O(n log k) for n elements in k slices.
+func MergeSortedSlices[T any](less func(T, T) bool, slices ...[]T) []T {
+	total := 0
+	for _, s := range slices {
+		total += len(s)
+	}
+	out := make([]T, 0, total)
+
+	heads := make([]T, len(slices))
+	pos := make([]int, len(slices))
+	inputs := NewHeap[int](mergeArity, mergeLess(less, heads), WithoutIndex[int]())
+	for i, s := range slices {
+		if len(s) > 0 {
+			heads[i] = s[0]
+			inputs.Push(i)
+		}
+	}
+	for !inputs.IsEmpty() {
+		i := inputs.Peek()
+		out = append(out, heads[i])
+		if pos[i]++; pos[i] < len(slices[i]) {
+			heads[i] = slices[i][pos[i]]
+			inputs.Replace(i)
+		} else {
+			inputs.Pop()
+		}
+	}
+	return out
+}
+
+// mergeLess orders input numbers by their current heads, breaking ties by
+// input number so that merges are stable.
+func mergeLess[T any](less func(T, T) bool, heads []T) func(i, j int) bool {
+	return func(i, j int) bool {
+		switch {
+		case less(heads[i], heads[j]):
+			return true
+		case less(heads[j], heads[i]):
+			return false
+		default:
+			return i < j
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package heap
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSorted(t *testing.T) {
+	t.Parallel()
+
+	type entry struct {
+		key, input int
+	}
+	less := func(a, b entry) bool { return a.key < b.key }
+
+	rng := rand.New(rand.NewSource(3))
+	inputs := make([][]entry, 7)
+	var want []entry
+	for i := range inputs {
+		n := rng.Intn(30) // Some inputs may be empty
+		for k := 0; k < n; k++ {
+			inputs[i] = append(inputs[i], entry{rng.Intn(50), i})
+		}
+		sort.SliceStable(inputs[i], func(a, b int) bool { return less(inputs[i][a], inputs[i][b]) })
+		want = append(want, inputs[i]...)
+	}
+	sort.SliceStable(want, func(a, b int) bool { return less(want[a], want[b]) })
+
+	seqs := make([]iter.Seq[entry], len(inputs))
+	for i, in := range inputs {
+		seqs[i] = slices.Values(in)
+	}
+	assert.Equal(t, want, slices.Collect(MergeSorted(less, seqs...)))
+	assert.Equal(t, want, MergeSortedSlices(less, inputs...))
+
+	// Stopping early releases the inputs.
+	var first []entry
+	for e := range MergeSorted(less, seqs...) {
+		if first = append(first, e); len(first) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, want[:3], first)
+
+	assert.Empty(t, slices.Collect(MergeSorted[int](func(a, b int) bool { return a < b })))
+	assert.Empty(t, MergeSortedSlices(func(a, b int) bool { return a < b }, nil, []int{}))
+}
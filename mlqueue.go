@@ -0,0 +1,130 @@
+package heap
+
+import "fmt"
+
+// MLQueue is a multi-level queue that keeps a separate heap per priority
+// class and serves the classes in weighted round robin by quota, so a flood
+// of high-priority elements cannot starve the lower classes. With quotas 8, 2
+// and 1, every 11 pops take 8 elements from class 0, 2 from class 1 and 1
+// from class 2. A class with nothing queued yields its turn to the next, so
+// the queue never idles while any class holds an element.
+//
+// MLQueue implements Queue with the class as the priority. Elements can be
+// removed by value or by the handles returned from PushHandle, as with Heap.
+type MLQueue[T comparable] struct {
+	classes []*Heap[T] // Heap of each class, class 0 first in the rotation
+	quotas  []int      // Pops each class is allowed per turn
+	current int        // Class whose turn it is
+	served  int        // Pops taken from the current class this turn
+}
+
+// NewMLQueue creates an empty multi-level queue with one class per quota.
+// Each class is a d-ary heap ordered by less and configured with options.
+// It panics if a quota is not positive.
+func NewMLQueue[T comparable](d int, less func(T, T) bool, quotas []int, options ...Option[T]) *MLQueue[T] {
+	q := &MLQueue[T]{
+		classes: make([]*Heap[T], len(quotas)),
+		quotas:  append([]int(nil), quotas...),
+	}
+	for i, quota := range quotas {
+		if quota < 1 {
+			panic(fmt.Sprintf("heap: quota %d of class %d is not positive", quota, i))
+		}
+		q.classes[i] = NewHeap(d, less, options...)
+	}
+	return q
+}
+
+// Len returns the number of elements in the queue.
+func (q *MLQueue[T]) Len() int {
+	n := 0
+	for _, h := range q.classes {
+		n += h.Len()
+	}
+	return n
+}
+
+// ClassLen returns the number of elements queued in the given class.
+func (q *MLQueue[T]) ClassLen(class int) int {
+	return q.classes[class].Len()
+}
+
+// Push adds value to the given class. It panics if the class is out of range.
+func (q *MLQueue[T]) Push(value T, class int) {
+	q.classes[class].Push(value)
+}
+
+// PushHandle adds value to the given class and returns a handle to it for
+// RemoveHandle.
+func (q *MLQueue[T]) PushHandle(value T, class int) *Item[T] {
+	it := q.classes[class].PushHandle(value)
+	return it
+}
+
+// Remove removes one occurrence of element from whichever class holds it,
+// searching the classes in order. It returns false if no class holds it.
+func (q *MLQueue[T]) Remove(element T) bool {
+	for _, h := range q.classes {
+		if h.Remove(element) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveHandle removes the element it refers to. It returns false if the
+// element has already left the queue.
+func (q *MLQueue[T]) RemoveHandle(it *Item[T]) bool {
+	for _, h := range q.classes {
+		if h.RemoveHandle(it) {
+			return true
+		}
+	}
+	return false
+}
+
+// Peek returns the element Pop would return next and its class without
+// removing it. If the queue is empty, it returns the zero value, -1 and false.
+func (q *MLQueue[T]) Peek() (T, int, bool) {
+	class, _ := q.next()
+	if class < 0 {
+		var zero T
+		return zero, -1, false
+	}
+	return q.classes[class].Peek(), class, true
+}
+
+// Pop removes and returns the extremal element of the class whose turn it is,
+// together with that class. If the queue is empty, it returns the zero value
+// and -1.
+func (q *MLQueue[T]) Pop() (T, int) {
+	class, turn := q.next()
+	if class < 0 {
+		var zero T
+		return zero, -1
+	}
+	if turn {
+		q.current, q.served = class, 0
+	}
+	q.served++
+	return q.classes[class].Pop(), class
+}
+
+// next returns the class to serve next, or -1 if the queue is empty, and
+// whether serving it starts a new turn.
+func (q *MLQueue[T]) next() (int, bool) {
+	n := len(q.classes)
+	if n == 0 {
+		return -1, false
+	}
+	for k := 0; k <= n; k++ {
+		class := (q.current + k) % n
+		if k == 0 && q.served >= q.quotas[class] {
+			continue
+		}
+		if !q.classes[class].IsEmpty() {
+			return class, k > 0
+		}
+	}
+	return -1, false
+}
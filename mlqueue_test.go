@@ -0,0 +1,73 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMLQueueQuotas(t *testing.T) {
+	t.Parallel()
+
+	q := NewMLQueue[int](4, func(a, b int) bool { return a < b }, []int{3, 2, 1})
+	for i := 0; i < 20; i++ {
+		q.Push(i, 0)
+		q.Push(100+i, 1)
+		q.Push(200+i, 2)
+	}
+	assert.Equal(t, 60, q.Len())
+
+	var classes []int
+	for i := 0; i < 12; i++ {
+		_, class, ok := q.Peek()
+		assert.True(t, ok)
+		v, popped := q.Pop()
+		assert.Equal(t, class, popped, "Peek predicts Pop")
+		assert.Equal(t, class*100, v-v%100)
+		classes = append(classes, class)
+	}
+	assert.Equal(t, []int{0, 0, 0, 1, 1, 2, 0, 0, 0, 1, 1, 2}, classes)
+	assert.Equal(t, 14, q.ClassLen(0))
+}
+
+func TestMLQueueEmptyClasses(t *testing.T) {
+	t.Parallel()
+
+	q := NewMLQueue[int](2, func(a, b int) bool { return a < b }, []int{8, 2, 1})
+	_, class := q.Pop()
+	assert.Equal(t, -1, class)
+
+	q.Push(1, 2)
+	q.Push(2, 2)
+	q.Push(3, 1)
+	var got []int
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{3, 1, 2}, got, "empty classes yield their turn")
+
+	_, _, ok := q.Peek()
+	assert.False(t, ok)
+	assert.Panics(t, func() { NewMLQueue[int](2, func(a, b int) bool { return a < b }, []int{1, 0}) })
+	_, class = NewMLQueue[int](2, func(a, b int) bool { return a < b }, nil).Pop()
+	assert.Equal(t, -1, class)
+}
+
+func TestMLQueueRemove(t *testing.T) {
+	t.Parallel()
+
+	q := NewMLQueue[string](2, func(a, b string) bool { return a < b }, []int{1, 1})
+	q.Push("a", 0)
+	it := q.PushHandle("b", 1)
+	q.Push("c", 1)
+
+	assert.True(t, q.Remove("a"))
+	assert.False(t, q.Remove("a"))
+	assert.True(t, q.RemoveHandle(it))
+	assert.False(t, q.RemoveHandle(it))
+	assert.Equal(t, 1, q.Len())
+	v, class := q.Pop()
+	assert.Equal(t, "c", v)
+	assert.Equal(t, 1, class)
+}
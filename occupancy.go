@@ -0,0 +1,50 @@
+package heap
+
+// occupancyTracker maintains an exponentially weighted moving average of the
+// heap size, sampled after every push and pop.
+type occupancyTracker struct {
+	alpha   float64
+	average float64
+	primed  bool
+}
+
+// WithOccupancy is an option that tracks a decaying average of the heap size,
+// updated after every push and pop and weighting the newest sample by alpha,
+// which must be in (0, 1]. Smaller values of alpha average over longer
+// histories; 1/n roughly averages over the last n operations.
+func WithOccupancy[T comparable](alpha float64) Option[T] {
+	return func(h *Heap[T]) {
+		h.occupancy = &occupancyTracker{alpha: alpha}
+	}
+}
+
+// HighWaterMark returns the largest size the heap has reached since it was
+// created or since ResetHighWaterMark was last called. Comparing it with the
+// configured capacity helps right-size WithCapacity.
+func (h *Heap[T]) HighWaterMark() int {
+	return h.highWater
+}
+
+// ResetHighWaterMark resets the high-water mark to the current size.
+func (h *Heap[T]) ResetHighWaterMark() {
+	h.highWater = h.heapSize
+}
+
+// AverageOccupancy returns the decaying average size of the heap. A value that
+// keeps rising while traffic is steady points to elements that are pushed but
+// never popped. It returns zero unless the heap was created with WithOccupancy.
+func (h *Heap[T]) AverageOccupancy() float64 {
+	if h.occupancy == nil {
+		return 0
+	}
+	return h.occupancy.average
+}
+
+// sample folds the current size into the average.
+func (o *occupancyTracker) sample(size int) {
+	if !o.primed {
+		o.average, o.primed = float64(size), true
+		return
+	}
+	o.average += o.alpha * (float64(size) - o.average)
+}
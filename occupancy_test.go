@@ -0,0 +1,39 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighWaterMark(t *testing.T) {
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	assert.Zero(t, heap.HighWaterMark())
+
+	for i := 0; i < 10; i++ {
+		heap.Push(i)
+	}
+	for i := 0; i < 7; i++ {
+		heap.Pop()
+	}
+	assert.Equal(t, 10, heap.HighWaterMark())
+
+	heap.ResetHighWaterMark()
+	assert.Equal(t, 3, heap.HighWaterMark())
+	heap.Push(1)
+	assert.Equal(t, 4, heap.HighWaterMark())
+}
+
+func TestAverageOccupancy(t *testing.T) {
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b }, WithOccupancy[int](0.5))
+	heap.Push(1)
+	assert.Equal(t, 1.0, heap.AverageOccupancy())
+	heap.Push(2)
+	assert.Equal(t, 1.5, heap.AverageOccupancy())
+	heap.Pop()
+	assert.Equal(t, 1.25, heap.AverageOccupancy())
+
+	plain := NewHeap[int](2, func(a, b int) bool { return a < b })
+	plain.Push(1)
+	assert.Zero(t, plain.AverageOccupancy())
+}
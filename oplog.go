@@ -0,0 +1,67 @@
+package heap
+
+// OpKind identifies the kind of mutation recorded in an operation log.
+type OpKind int
+
+const (
+	// OpPush records that Value was pushed.
+	OpPush OpKind = iota
+	// OpPop records that Value was popped.
+	OpPop
+	// OpRemove records that Value was removed from an arbitrary position.
+	OpRemove
+	// OpUpdate records that Old was replaced by Value.
+	OpUpdate
+	// OpReset records that the heap was emptied.
+	OpReset
+)
+
+// Op is one entry of an operation log.
+type Op[T comparable] struct {
+	Seq   uint64 // Position in the log, starting at 1 with no gaps
+	Kind  OpKind
+	Value T
+	Old   T // Previous value for OpUpdate
+}
+
+// WithOpLog is an option that passes every mutation of the heap to fn, in
+// order and numbered by sequence, so a standby can mirror the heap with Apply
+// or an event-sourcing system can persist it. Bulk operations such as CopyFrom
+// are logged as an OpReset followed by an OpPush per element. fn is called
+// synchronously from the mutating method.
+func WithOpLog[T comparable](fn func(Op[T])) Option[T] {
+	return func(h *Heap[T]) {
+		h.oplog = fn
+	}
+}
+
+// SendOps returns a log function for WithOpLog that sends every operation on
+// ch. Sends block, so ch should be buffered or drained promptly.
+func SendOps[T comparable](ch chan<- Op[T]) func(Op[T]) {
+	return func(op Op[T]) { ch <- op }
+}
+
+// Apply replays a logged operation against h. Replaying a log in order on an
+// empty heap with the same comparator reproduces the logged heap's elements.
+// Pops are replayed as pops of h's own extremal element.
+func (h *Heap[T]) Apply(op Op[T]) {
+	switch op.Kind {
+	case OpPush:
+		h.Push(op.Value)
+	case OpPop:
+		h.Pop()
+	case OpRemove:
+		h.Remove(op.Value)
+	case OpUpdate:
+		h.Update(op.Old, op.Value)
+	case OpReset:
+		h.reset()
+	}
+}
+
+// log numbers op and passes it to the operation log.
+func (h *Heap[T]) log(op Op[T]) {
+	h.opSeq++
+	op.Seq = h.opSeq
+	h.oplog(op)
+}
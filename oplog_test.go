@@ -0,0 +1,69 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpLog(t *testing.T) {
+	var ops []Op[int]
+	less := func(a, b int) bool { return a < b }
+	primary := NewHeap[int](2, less, WithOpLog[int](func(op Op[int]) { ops = append(ops, op) }))
+
+	primary.Push(5)
+	primary.Push(1)
+	primary.Pop()
+	primary.Push(3)
+
+	assert.Equal(t, []Op[int]{
+		{Seq: 1, Kind: OpPush, Value: 5},
+		{Seq: 2, Kind: OpPush, Value: 1},
+		{Seq: 3, Kind: OpPop, Value: 1},
+		{Seq: 4, Kind: OpPush, Value: 3},
+	}, ops)
+
+	template := NewHeap[int](2, less)
+	template.Push(7)
+	template.Push(2)
+	primary.CopyFrom(template)
+	assert.Equal(t, OpReset, ops[4].Kind)
+	assert.Len(t, ops, 7)
+
+	// A standby replaying the log mirrors the primary.
+	standby := NewHeap[int](4, less)
+	for _, op := range ops {
+		standby.Apply(op)
+	}
+	assert.Equal(t, primary.heapSize, standby.heapSize)
+	for primary.heapSize > 0 {
+		assert.Equal(t, primary.Pop(), standby.Pop())
+	}
+}
+
+func TestApplyRemoveAndUpdate(t *testing.T) {
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	for _, v := range []int{4, 2, 9} {
+		heap.Push(v)
+	}
+
+	heap.Apply(Op[int]{Kind: OpRemove, Value: 2})
+	heap.Apply(Op[int]{Kind: OpUpdate, Old: 9, Value: 1})
+	assert.Equal(t, 1, heap.Pop())
+	assert.Equal(t, 4, heap.Pop())
+	assert.Zero(t, heap.heapSize)
+}
+
+func TestSendOps(t *testing.T) {
+	ch := make(chan Op[string], 4)
+	heap := NewHeap[string](2, func(a, b string) bool { return a < b }, WithOpLog(SendOps(ch)))
+	heap.Push("a")
+	heap.Pop()
+	close(ch)
+
+	var kinds []OpKind
+	for op := range ch {
+		kinds = append(kinds, op.Kind)
+	}
+	assert.Equal(t, []OpKind{OpPush, OpPop}, kinds)
+}
@@ -0,0 +1,74 @@
+package heap
+
+// Optional holds either a value or nothing. It is returned by the Optional
+// variants of lookups so call sites that fall back to a default can chain
+// calls instead of unpacking a (T, bool) pair.
+type Optional[T any] struct {
+	value T
+	ok    bool
+}
+
+// Some returns an Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, ok: true}
+}
+
+// None returns an empty Optional.
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// IsSome reports whether o holds a value.
+func (o Optional[T]) IsSome() bool {
+	return o.ok
+}
+
+// IsNone reports whether o is empty.
+func (o Optional[T]) IsNone() bool {
+	return !o.ok
+}
+
+// Value returns the held value, or the zero value of type T if o is empty.
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// OrElse returns the held value, or fallback if o is empty.
+func (o Optional[T]) OrElse(fallback T) T {
+	if !o.ok {
+		return fallback
+	}
+	return o.value
+}
+
+// Get returns the held value and whether there was one.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// PeekOptional returns the extremal element without removing it, or None if
+// the heap is empty.
+func (h *Heap[T]) PeekOptional() Optional[T] {
+	if h.IsEmpty() {
+		return None[T]()
+	}
+	return Some(h.Peek())
+}
+
+// PopOptional removes and returns the extremal element, or None if the heap
+// is empty.
+func (h *Heap[T]) PopOptional() Optional[T] {
+	if h.IsEmpty() {
+		return None[T]()
+	}
+	return Some(h.Pop())
+}
+
+// GetOptional retrieves the first occurrence of element like Get, returning
+// None if it is not in the heap.
+func (h *Heap[T]) GetOptional(element T) Optional[T] {
+	if v, ok := h.Get(element); ok {
+		return Some(v)
+	}
+	return None[T]()
+}
@@ -0,0 +1,42 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptional(t *testing.T) {
+	some := Some(3)
+	assert.True(t, some.IsSome())
+	assert.False(t, some.IsNone())
+	assert.Equal(t, 3, some.Value())
+	assert.Equal(t, 3, some.OrElse(7))
+
+	none := None[int]()
+	assert.False(t, none.IsSome())
+	assert.True(t, none.IsNone())
+	assert.Zero(t, none.Value())
+	assert.Equal(t, 7, none.OrElse(7))
+
+	v, ok := none.Get()
+	assert.False(t, ok)
+	assert.Zero(t, v)
+}
+
+func TestHeapOptionalVariants(t *testing.T) {
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+
+	assert.Equal(t, -1, heap.PeekOptional().OrElse(-1))
+	assert.Equal(t, -1, heap.PopOptional().OrElse(-1))
+	assert.True(t, heap.GetOptional(4).IsNone())
+
+	heap.Push(4)
+	heap.Push(2)
+
+	assert.Equal(t, 2, heap.PeekOptional().OrElse(-1))
+	assert.Equal(t, 4, heap.GetOptional(4).OrElse(-1))
+	assert.Equal(t, 2, heap.PopOptional().OrElse(-1))
+	assert.Equal(t, 4, heap.PopOptional().OrElse(-1))
+	assert.True(t, heap.PopOptional().IsNone())
+}
@@ -0,0 +1,117 @@
+package heap
+
+import "math/bits"
+
+// PagedHeap is a d-ary heap whose elements live in fixed-size pages instead of
+// one contiguous slice. Growing it allocates one more page rather than copying
+// every element into a larger array, so heaps of hundreds of millions of
+// elements avoid giant allocations and copy-on-grow stalls. Pages are a power
+// of two in size, so an element is located with a shift and a mask.
+//
+// PagedHeap is a separate type rather than a storage option on Heap. Heap
+// reads its contiguous slice directly on every hot path and hands it out
+// through WithBackingSlice and the slice-based readers, and routing all of
+// that through page arithmetic would slow every heap to help a few very large
+// ones. PagedHeap therefore offers only Len, Push, Pop and Peek: it takes no
+// options, runs no hooks and keeps no element index, which at this scale
+// would cost more than the storage itself, so it has no Contains, Get, Remove
+// or Update.
+type PagedHeap[T comparable] struct {
+	pages    [][]T           // Fixed-size pages holding the elements in heap order
+	shift    int             // log2 of the page size
+	mask     int             // Page size minus one
+	d        int             // Branching factor
+	heapSize int             // Current size of the heap
+	lessFunc func(T, T) bool // Function to determine order
+}
+
+// NewPagedHeap creates an empty paged d-ary heap. pageSize is rounded up to a
+// power of two.
+func NewPagedHeap[T comparable](d int, lessFunc func(T, T) bool, pageSize int) *PagedHeap[T] {
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	shift := bits.Len(uint(pageSize - 1))
+	return &PagedHeap[T]{
+		shift:    shift,
+		mask:     1<<shift - 1,
+		d:        d,
+		lessFunc: lessFunc,
+	}
+}
+
+// Len returns the number of elements in the heap.
+func (h *PagedHeap[T]) Len() int {
+	return h.heapSize
+}
+
+// at returns a pointer to the element at index i.
+func (h *PagedHeap[T]) at(i int) *T {
+	return &h.pages[i>>h.shift][i&h.mask]
+}
+
+// Peek returns the minimum element from the heap without removing it.
+func (h *PagedHeap[T]) Peek() T {
+	if h.heapSize == 0 {
+		var zero T
+		return zero
+	}
+	return h.pages[0][0]
+}
+
+// Push adds a new element to the heap, allocating a new page if the last one
+// is full.
+func (h *PagedHeap[T]) Push(value T) {
+	if h.heapSize>>h.shift == len(h.pages) {
+		h.pages = append(h.pages, make([]T, h.mask+1))
+	}
+	i := h.heapSize
+	h.heapSize++
+	for i > 0 {
+		p := (i - 1) / h.d
+		parent := h.at(p)
+		if !h.lessFunc(value, *parent) {
+			break
+		}
+		*h.at(i) = *parent
+		i = p
+	}
+	*h.at(i) = value
+}
+
+// Pop removes and returns the minimum element from the heap. A page emptied by
+// the removal is released once the heap has shrunk a full page below it, so a
+// heap hovering at a page boundary does not allocate on every push.
+func (h *PagedHeap[T]) Pop() T {
+	var zero T
+	if h.heapSize == 0 {
+		return zero
+	}
+	minValue := h.pages[0][0]
+	h.heapSize--
+	last := *h.at(h.heapSize)
+	*h.at(h.heapSize) = zero
+	if len(h.pages) > h.heapSize>>h.shift+2 {
+		h.pages[len(h.pages)-1] = nil
+		h.pages = h.pages[:len(h.pages)-1]
+	}
+
+	// Move the hole at the root down to where the last element belongs.
+	i := 0
+	for h.heapSize > 0 {
+		best := -1
+		first := h.d*i + 1
+		for c := first; c < first+h.d && c < h.heapSize; c++ {
+			if best < 0 || h.lessFunc(*h.at(c), *h.at(best)) {
+				best = c
+			}
+		}
+		if best < 0 || !h.lessFunc(*h.at(best), last) {
+			*h.at(i) = last
+			break
+		}
+		*h.at(i) = *h.at(best)
+		i = best
+	}
+	return minValue
+}
@@ -0,0 +1,66 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagedHeap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		d        int
+		pageSize int
+		n        int
+	}{
+		{name: "single page", d: 2, pageSize: 64, n: 50},
+		{name: "many pages", d: 4, pageSize: 8, n: 1000},
+		{name: "page size rounded up", d: 3, pageSize: 5, n: 300},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewSource(int64(tt.n)))
+			heap := NewPagedHeap[int](tt.d, func(a, b int) bool { return a < b }, tt.pageSize)
+			values := make([]int, tt.n)
+			for i := range values {
+				values[i] = rng.Intn(100)
+				heap.Push(values[i])
+			}
+			assert.Equal(t, tt.n, heap.Len())
+
+			sort.Ints(values)
+			assert.Equal(t, values[0], heap.Peek())
+			for _, want := range values {
+				assert.Equal(t, want, heap.Pop())
+			}
+			assert.Zero(t, heap.Len())
+			assert.Zero(t, heap.Pop())
+			assert.LessOrEqual(t, len(heap.pages), 2, "empty pages not released")
+		})
+	}
+}
+
+func TestPagedHeapMatchesHeap(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(3))
+	paged := NewPagedHeap[int](4, func(a, b int) bool { return a > b }, 16)
+	reference := NewHeap[int](4, func(a, b int) bool { return a > b })
+	for i := 0; i < 5000; i++ {
+		if rng.Intn(5) < 2 {
+			assert.Equal(t, reference.Pop(), paged.Pop())
+			continue
+		}
+		v := rng.Intn(1000)
+		paged.Push(v)
+		reference.Push(v)
+	}
+}
@@ -0,0 +1,180 @@
+// Package pairing provides a generic pairing heap. It mirrors the API of the
+// d-ary heap package, taking the same less function, and adds O(1) Meld and
+// handle-based DecreaseKey. Because it is pointer-based, it can beat the
+// array-backed d-ary heap on workloads dominated by decrease-key and melds,
+// such as Prim's algorithm on dense graphs.
+package pairing
+
+// Node is a handle to an element in the heap.
+type Node[T any] struct {
+	value   T
+	child   *Node[T] // Leftmost child
+	sibling *Node[T] // Next sibling to the right
+	prev    *Node[T] // Left sibling, or parent for a leftmost child
+	owner   *owner   // Identifies the heap holding the node, nil once popped
+}
+
+// owner identifies a heap for membership checks on handles. Melding a heap
+// into another links the absorbed heap's owner to the absorbing one, so
+// handles are re-pointed in O(1) and resolved with path compression.
+type owner struct {
+	parent *owner
+}
+
+// find returns the owner o currently resolves to.
+func (o *owner) find() *owner {
+	root := o
+	for root.parent != nil {
+		root = root.parent
+	}
+	for o != root {
+		o, o.parent = o.parent, root
+	}
+	return root
+}
+
+// Value returns the element the handle refers to.
+func (n *Node[T]) Value() T {
+	return n.value
+}
+
+// Heap is a pairing heap ordered by a less function.
+type Heap[T any] struct {
+	root     *Node[T]
+	size     int
+	lessFunc func(T, T) bool
+	owner    *owner
+}
+
+// NewHeap creates an empty pairing heap ordered by lessFunc.
+func NewHeap[T any](lessFunc func(T, T) bool) *Heap[T] {
+	return &Heap[T]{lessFunc: lessFunc, owner: &owner{}}
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return h.size
+}
+
+// Peek returns the extremal element without removing it. If the heap is
+// empty, it returns the zero value of type T.
+func (h *Heap[T]) Peek() T {
+	if h.root == nil {
+		var zero T
+		return zero
+	}
+	return h.root.value
+}
+
+// Push adds a new element to the heap in O(1) and returns a handle to it.
+func (h *Heap[T]) Push(value T) *Node[T] {
+	n := &Node[T]{value: value, owner: h.owner}
+	h.root = h.link(h.root, n)
+	h.size++
+	return n
+}
+
+// Pop removes and returns the extremal element in O(log n) amortized time.
+// If the heap is empty, it returns the zero value of type T.
+func (h *Heap[T]) Pop() T {
+	if h.root == nil {
+		var zero T
+		return zero
+	}
+	top := h.root
+	h.root = h.mergePairs(top.child)
+	if h.root != nil {
+		h.root.prev = nil
+	}
+	h.size--
+	top.child, top.owner = nil, nil
+	return top.value
+}
+
+// Meld moves every element of other into h in O(1) and leaves other empty.
+// Both heaps must order elements the same way. Handles into other stay valid
+// and now refer to elements of h.
+func (h *Heap[T]) Meld(other *Heap[T]) {
+	if h == other || other.root == nil {
+		return
+	}
+	h.root = h.link(h.root, other.root)
+	h.size += other.size
+	other.owner.parent = h.owner
+	other.root, other.size, other.owner = nil, 0, &owner{}
+}
+
+// DecreaseKey replaces the element n refers to with value, which must not
+// order after it, in O(1) amortized time. It returns false without changing
+// anything if n is not in h or value orders after the current element.
+func (h *Heap[T]) DecreaseKey(n *Node[T], value T) bool {
+	if n.owner == nil || n.owner.find() != h.owner || h.lessFunc(n.value, value) {
+		return false
+	}
+	n.value = value
+	if n == h.root {
+		return true
+	}
+	h.detach(n)
+	h.root = h.link(h.root, n)
+	return true
+}
+
+// link makes the root that orders later the leftmost child of the other and
+// returns the new root. Either may be nil.
+func (h *Heap[T]) link(a, b *Node[T]) *Node[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.lessFunc(b.value, a.value) {
+		a, b = b, a
+	}
+	b.prev, b.sibling = a, a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+	a.prev, a.sibling = nil, nil
+	return a
+}
+
+// detach cuts the subtree rooted at n out of the tree.
+func (h *Heap[T]) detach(n *Node[T]) {
+	if n.prev.child == n {
+		n.prev.child = n.sibling
+	} else {
+		n.prev.sibling = n.sibling
+	}
+	if n.sibling != nil {
+		n.sibling.prev = n.prev
+	}
+	n.prev, n.sibling = nil, nil
+}
+
+// mergePairs merges a list of siblings with the standard two-pass scheme:
+// link them in pairs from left to right, then fold the pairs from right to
+// left.
+func (h *Heap[T]) mergePairs(first *Node[T]) *Node[T] {
+	var pairs []*Node[T]
+	for first != nil {
+		a, b := first, first.sibling
+		if b == nil {
+			first = nil
+		} else {
+			first = b.sibling
+		}
+		a.prev, a.sibling = nil, nil
+		if b != nil {
+			b.prev, b.sibling = nil, nil
+		}
+		pairs = append(pairs, h.link(a, b))
+	}
+	var root *Node[T]
+	for i := len(pairs) - 1; i >= 0; i-- {
+		root = h.link(pairs[i], root)
+	}
+	return root
+}
@@ -0,0 +1,73 @@
+package pairing
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	heap "github.com/ahrav/go-d-ary-heap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairingHeap(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(1))
+	heap := NewHeap[int](func(a, b int) bool { return a < b })
+	assert.Zero(t, heap.Pop())
+
+	values := make([]int, 500)
+	nodes := make([]*Node[int], len(values))
+	for i := range values {
+		values[i] = rng.Intn(1000) + 1000
+		nodes[i] = heap.Push(values[i])
+	}
+	for i := 0; i < len(values); i += 3 {
+		values[i] -= rng.Intn(1500)
+		assert.True(t, heap.DecreaseKey(nodes[i], values[i]))
+		assert.Equal(t, values[i], nodes[i].Value())
+	}
+	assert.False(t, heap.DecreaseKey(nodes[1], values[1]+1), "increase accepted")
+
+	sort.Ints(values)
+	assert.Equal(t, values[0], heap.Peek())
+	for _, want := range values {
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.Zero(t, heap.Len())
+	assert.False(t, heap.DecreaseKey(nodes[0], -1), "popped node accepted")
+}
+
+func TestPairingHeapMeld(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	a, b, c := NewHeap(less), NewHeap(less), NewHeap(less)
+	a.Push(5)
+	a.Push(9)
+	nb := b.Push(7)
+	c.Push(3)
+	nc := c.Push(8)
+
+	b.Meld(c)
+	a.Meld(b)
+	a.Meld(a)
+	assert.Equal(t, 5, a.Len())
+	assert.Zero(t, b.Len())
+	assert.Zero(t, c.Len())
+
+	// Handles follow their elements through both melds.
+	assert.False(t, c.DecreaseKey(nc, 1))
+	assert.True(t, a.DecreaseKey(nc, 1))
+	assert.True(t, a.DecreaseKey(nb, 2))
+
+	// Melded-away heaps remain usable.
+	c.Push(4)
+	assert.Equal(t, 4, c.Pop())
+
+	for _, want := range []int{1, 2, 3, 5, 9} {
+		assert.Equal(t, want, a.Pop())
+	}
+}
+
+var _ heap.AddressableHeap[int, *Node[int]] = (*Heap[int])(nil)
@@ -0,0 +1,145 @@
+package heap
+
+import (
+	"iter"
+	"time"
+)
+
+// PriorityQueue is a d-ary heap of payloads ordered by a separate priority.
+// Unlike Heap, the payload type V does not need to be ordered.
+type PriorityQueue[V any, P any] struct {
+	entries []pqEntry[V, P] // Payload and priority of each slot
+	free    []int           // Slots available for reuse
+	heap    *Heap[int]      // Occupied slots ordered by priority
+	less    func(P, P) bool // Function to determine the order of priorities
+	aging   *agingPolicy[P] // Aging policy, nil unless WithAging is used
+}
+
+// pqEntry is a payload together with its priority.
+type pqEntry[V any, P any] struct {
+	value     V
+	priority  P
+	effective P         // Aged priority the entry is ordered by under WithAging
+	enqueued  time.Time // When the entry was pushed under WithAging
+}
+
+// NewPriorityQueue creates an empty priority queue with branching factor d
+// whose priorities are ordered by less.
+func NewPriorityQueue[V any, P any](d int, less func(P, P) bool, options ...PQOption[V, P]) *PriorityQueue[V, P] {
+	q := &PriorityQueue[V, P]{less: less}
+	for _, option := range options {
+		option(q)
+	}
+	if q.aging != nil {
+		q.heap = NewHeap[int](d, func(a, b int) bool {
+			return q.less(q.entries[a].effective, q.entries[b].effective)
+		})
+		return q
+	}
+	q.heap = NewHeap[int](d, func(a, b int) bool {
+		return q.less(q.entries[a].priority, q.entries[b].priority)
+	})
+	return q
+}
+
+// Len returns the number of elements in the queue.
+func (q *PriorityQueue[V, P]) Len() int {
+	return q.heap.heapSize
+}
+
+// Push adds value with the given priority.
+func (q *PriorityQueue[V, P]) Push(value V, priority P) {
+	e := pqEntry[V, P]{value: value, priority: priority}
+	if q.aging != nil {
+		e.enqueued = q.age()
+		e.effective = q.aging.effective(priority, 0)
+	}
+	if n := len(q.free); n > 0 {
+		slot := q.free[n-1]
+		q.free = q.free[:n-1]
+		q.entries[slot] = e
+		q.heap.Push(slot)
+		return
+	}
+	q.entries = append(q.entries, e)
+	q.heap.Push(len(q.entries) - 1)
+}
+
+// Peek returns the payload and priority of the extremal element without
+// removing it. If the queue is empty, it returns zero values and false.
+func (q *PriorityQueue[V, P]) Peek() (V, P, bool) {
+	if q.aging != nil {
+		q.age()
+	}
+	if q.heap.heapSize == 0 {
+		var e pqEntry[V, P]
+		return e.value, e.priority, false
+	}
+	e := q.entries[q.heap.Peek()]
+	return e.value, e.priority, true
+}
+
+// Pop removes and returns the payload and priority of the extremal element.
+// If the queue is empty, it returns zero values.
+func (q *PriorityQueue[V, P]) Pop() (V, P) {
+	if q.aging != nil {
+		q.age()
+	}
+	if q.heap.heapSize == 0 {
+		var e pqEntry[V, P]
+		return e.value, e.priority
+	}
+	slot := q.heap.Pop()
+	e := q.entries[slot]
+	q.entries[slot] = pqEntry[V, P]{} // Do not retain the payload
+	q.free = append(q.free, slot)
+	return e.value, e.priority
+}
+
+// All2 returns an iterator over the priorities and payloads in the queue in no
+// particular order, so range loops receive both like they do over a map. The
+// queue must not be modified during iteration.
+func (q *PriorityQueue[V, P]) All2() iter.Seq2[P, V] {
+	return func(yield func(P, V) bool) {
+		for _, slot := range q.heap.data[:q.heap.heapSize] {
+			if e := q.entries[slot]; !yield(e.priority, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted2 returns an iterator over the priorities and payloads in the queue in
+// priority order. The queue itself is not drained; the order is produced
+// lazily from a copy of the heap. The queue must not be modified during
+// iteration.
+func (q *PriorityQueue[V, P]) Sorted2() iter.Seq2[P, V] {
+	return func(yield func(P, V) bool) {
+		for slot := range IncrementalSortFunc(q.heap.data[:q.heap.heapSize], q.heap.d, q.heap.lessFunc) {
+			if e := q.entries[slot]; !yield(e.priority, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// UpdatePriority changes the priority of one payload equal to value to
+// priority and restores the queue order in O(log n). Finding the payload is a
+// linear scan, since payloads are not indexed. It is a function rather than a
+// method because it needs to compare payloads. It returns false if no
+// payload equals value.
+func UpdatePriority[V comparable, P any](q *PriorityQueue[V, P], value V, priority P) bool {
+	for i, slot := range q.heap.data[:q.heap.heapSize] {
+		if q.entries[slot].value != value {
+			continue
+		}
+		e := &q.entries[slot]
+		e.priority = priority
+		if a := q.aging; a != nil {
+			e.effective = a.effective(priority, a.clock.Now().Sub(e.enqueued))
+		}
+		q.heap.fix(i)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue[string, int](3, func(a, b int) bool { return a < b })
+
+	_, _, ok := q.Peek()
+	assert.False(t, ok)
+
+	q.Push("write report", 3)
+	q.Push("fix outage", 1)
+	q.Push("lunch", 5)
+	q.Push("review PR", 2)
+	assert.Equal(t, 4, q.Len())
+
+	v, p, ok := q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, "fix outage", v)
+	assert.Equal(t, 1, p)
+
+	v, p = q.Pop()
+	assert.Equal(t, "fix outage", v)
+	assert.Equal(t, 1, p)
+
+	// Freed slots are reused.
+	q.Push("page oncall", 0)
+	assert.Len(t, q.entries, 4)
+
+	var order []string
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		order = append(order, v)
+	}
+	assert.Equal(t, []string{"page oncall", "review PR", "write report", "lunch"}, order)
+}
+
+func TestPriorityQueueIterators(t *testing.T) {
+	q := NewPriorityQueue[string, int](2, func(a, b int) bool { return a > b })
+	q.Push("low", 1)
+	q.Push("high", 9)
+	q.Push("mid", 5)
+
+	all := make(map[int]string)
+	for p, v := range q.All2() {
+		all[p] = v
+	}
+	assert.Equal(t, map[int]string{1: "low", 5: "mid", 9: "high"}, all)
+
+	var priorities []int
+	var values []string
+	for p, v := range q.Sorted2() {
+		priorities = append(priorities, p)
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{9, 5, 1}, priorities)
+	assert.Equal(t, []string{"high", "mid", "low"}, values)
+	assert.Equal(t, 3, q.Len(), "Sorted2 drained the queue")
+
+	for p := range q.Sorted2() {
+		assert.Equal(t, 9, p)
+		break
+	}
+}
+
+func TestPriorityQueueUpdatePriority(t *testing.T) {
+	q := NewPriorityQueue[string, int](3, func(a, b int) bool { return a < b })
+	q.Push("write", 5)
+	q.Push("read", 3)
+	q.Push("flush", 9)
+	q.Push("sync", 7)
+
+	assert.True(t, UpdatePriority(q, "flush", 1))
+	assert.True(t, UpdatePriority(q, "read", 8))
+	assert.False(t, UpdatePriority(q, "close", 0))
+
+	for _, want := range []string{"flush", "write", "sync", "read"} {
+		v, _ := q.Pop()
+		assert.Equal(t, want, v)
+	}
+}
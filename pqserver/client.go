@@ -0,0 +1,93 @@
+package pqserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Client talks to a Server.
+type Client[T comparable] struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient creates a client for the server at baseURL. If httpClient is nil,
+// http.DefaultClient is used.
+func NewClient[T comparable](baseURL string, httpClient *http.Client) *Client[T] {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client[T]{baseURL: strings.TrimSuffix(baseURL, "/"), http: httpClient}
+}
+
+// Enqueue adds value to the remote queue.
+func (c *Client[T]) Enqueue(ctx context.Context, value T) error {
+	body, err := json.Marshal(valueMessage[T]{Value: value})
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPost, "/enqueue", body, nil)
+	return err
+}
+
+// Dequeue removes and returns the extremal element of the remote queue. It
+// returns false if the queue is empty.
+func (c *Client[T]) Dequeue(ctx context.Context) (T, bool, error) {
+	return c.value(ctx, http.MethodPost, "/dequeue")
+}
+
+// Peek returns the extremal element of the remote queue without removing it.
+// It returns false if the queue is empty.
+func (c *Client[T]) Peek(ctx context.Context) (T, bool, error) {
+	return c.value(ctx, http.MethodGet, "/peek")
+}
+
+// Len returns the number of elements in the remote queue.
+func (c *Client[T]) Len(ctx context.Context) (int, error) {
+	var msg lenMessage
+	_, err := c.do(ctx, http.MethodGet, "/len", nil, &msg)
+	return msg.Len, err
+}
+
+// value performs a request answered by an element or No Content.
+func (c *Client[T]) value(ctx context.Context, method, path string) (T, bool, error) {
+	var msg valueMessage[T]
+	status, err := c.do(ctx, method, path, nil, &msg)
+	if err != nil || status == http.StatusNoContent {
+		var zero T
+		return zero, false, err
+	}
+	return msg.Value, true, nil
+}
+
+// do sends a request and decodes a successful JSON response into out.
+func (c *Client[T]) do(ctx context.Context, method, path string, body []byte, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		return resp.StatusCode, nil
+	case resp.StatusCode != http.StatusOK:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("pqserver: %s %s: %s: %s", method, path, resp.Status, bytes.TrimSpace(msg))
+	case out != nil:
+		return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,104 @@
+// Package pqserver exposes a priority queue over HTTP with a matching Go
+// client, for services that are little more than a shared queue.
+//
+// The server speaks JSON:
+//
+//	POST /enqueue  {"value": v}   enqueue v, 204 No Content
+//	POST /dequeue                  200 {"value": v}, or 204 No Content when empty
+//	GET  /peek                     200 {"value": v}, or 204 No Content when empty
+//	GET  /len                      200 {"len": n}
+//
+// Enqueue bodies larger than 1 MiB are rejected with 413 Request Entity Too
+// Large.
+package pqserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// maxBodySize is the largest enqueue request body the server accepts.
+const maxBodySize = 1 << 20
+
+// Backend is the queue served by a Server. heap.ConcurrentHeap satisfies it.
+// Implementations must be safe for concurrent use.
+type Backend[T comparable] interface {
+	Push(value T)
+	Pop() (T, bool)
+	Peek() (T, bool)
+	Len() int
+}
+
+// valueMessage is the JSON body carrying a single element.
+type valueMessage[T comparable] struct {
+	Value T `json:"value"`
+}
+
+// lenMessage is the JSON body carrying the queue length.
+type lenMessage struct {
+	Len int `json:"len"`
+}
+
+// Server is an http.Handler serving a Backend.
+type Server[T comparable] struct {
+	backend Backend[T]
+	mux     *http.ServeMux
+}
+
+// NewServer creates a Server for backend.
+func NewServer[T comparable](backend Backend[T]) *Server[T] {
+	s := &Server[T]{backend: backend, mux: http.NewServeMux()}
+	s.mux.HandleFunc("POST /enqueue", s.enqueue)
+	s.mux.HandleFunc("POST /dequeue", s.dequeue)
+	s.mux.HandleFunc("GET /peek", s.peek)
+	s.mux.HandleFunc("GET /len", s.length)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server[T]) enqueue(w http.ResponseWriter, r *http.Request) {
+	var msg valueMessage[T]
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&msg); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.backend.Push(msg.Value)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server[T]) dequeue(w http.ResponseWriter, _ *http.Request) {
+	writeValue(w, s.backend.Pop)
+}
+
+func (s *Server[T]) peek(w http.ResponseWriter, _ *http.Request) {
+	writeValue(w, s.backend.Peek)
+}
+
+func (s *Server[T]) length(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, lenMessage{Len: s.backend.Len()})
+}
+
+// writeValue writes the element returned by get, or No Content if there is none.
+func writeValue[T comparable](w http.ResponseWriter, get func() (T, bool)) {
+	v, ok := get()
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, valueMessage[T]{Value: v})
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
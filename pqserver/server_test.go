@@ -0,0 +1,69 @@
+package pqserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	heap "github.com/ahrav/go-d-ary-heap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientServerRoundTrip(t *testing.T) {
+	backend := heap.NewConcurrentHeap[int](4, func(a, b int) bool { return a < b })
+	srv := httptest.NewServer(NewServer[int](backend))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client := NewClient[int](srv.URL, srv.Client())
+
+	_, ok, err := client.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	for _, v := range []int{5, 2, 8} {
+		require.NoError(t, client.Enqueue(ctx, v))
+	}
+	n, err := client.Len(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	v, ok, err := client.Peek(ctx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	for _, want := range []int{2, 5, 8} {
+		v, ok, err := client.Dequeue(ctx)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, want, v)
+	}
+	assert.Zero(t, backend.Len())
+}
+
+func TestServerRejectsBadRequests(t *testing.T) {
+	srv := NewServer[int](heap.NewConcurrentHeap[int](2, func(a, b int) bool { return a < b }))
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/enqueue", strings.NewReader(`{"value":"x"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	huge := `{"value":1` + strings.Repeat(" ", maxBodySize) + `}`
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/enqueue", strings.NewReader(huge)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dequeue", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	client := NewClient[string](ts.URL, nil)
+	err := client.Enqueue(context.Background(), "not a number")
+	assert.ErrorContains(t, err, "400 Bad Request")
+}
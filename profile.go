@@ -0,0 +1,61 @@
+package heap
+
+// SiftDirection identifies which way a sift moved an element.
+type SiftDirection int
+
+const (
+	// SiftUp is the sift performed by Push.
+	SiftUp SiftDirection = iota
+	// SiftDown is the sift performed by Pop.
+	SiftDown
+)
+
+// SiftProfile is a histogram of sift depths: Up[n] and Down[n] count the
+// sifts that moved an element n levels.
+type SiftProfile struct {
+	Up   []int
+	Down []int
+}
+
+// siftProfiler records the depth of every sift.
+type siftProfiler struct {
+	hook    func(SiftDirection, int)
+	profile SiftProfile
+}
+
+// WithSiftProfile is an option that records how many levels each sift
+// performed by Push and Pop traverses, so the effect of the branching factor
+// on a real workload can be measured. If hook is not nil it is also called
+// after every sift.
+func WithSiftProfile[T comparable](hook func(dir SiftDirection, levels int)) Option[T] {
+	return func(h *Heap[T]) {
+		h.profile = &siftProfiler{hook: hook}
+	}
+}
+
+// SiftProfile returns a copy of the sift depth histogram. It returns the zero
+// value if the heap was not created with WithSiftProfile.
+func (h *Heap[T]) SiftProfile() SiftProfile {
+	if h.profile == nil {
+		return SiftProfile{}
+	}
+	return SiftProfile{
+		Up:   append([]int(nil), h.profile.profile.Up...),
+		Down: append([]int(nil), h.profile.profile.Down...),
+	}
+}
+
+// record adds a sift of the given depth to the histogram.
+func (p *siftProfiler) record(dir SiftDirection, levels int) {
+	counts := &p.profile.Up
+	if dir == SiftDown {
+		counts = &p.profile.Down
+	}
+	for len(*counts) <= levels {
+		*counts = append(*counts, 0)
+	}
+	(*counts)[levels]++
+	if p.hook != nil {
+		p.hook(dir, levels)
+	}
+}
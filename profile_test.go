@@ -0,0 +1,34 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSiftProfile(t *testing.T) {
+	var hooked []int
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b },
+		WithSiftProfile[int](func(dir SiftDirection, levels int) {
+			if dir == SiftUp {
+				hooked = append(hooked, levels)
+			}
+		}))
+
+	// Descending pushes into a binary min-heap bubble each element to the root.
+	for _, v := range []int{7, 6, 5, 4, 3, 2, 1} {
+		heap.Push(v)
+	}
+	assert.Equal(t, []int{0, 1, 1, 2, 2, 2, 2}, hooked)
+
+	heap.Pop()
+	profile := heap.SiftProfile()
+	assert.Equal(t, []int{1, 2, 4}, profile.Up)
+	assert.Equal(t, []int{0, 1}, profile.Down)
+}
+
+func TestSiftProfileDisabled(t *testing.T) {
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	heap.Push(1)
+	assert.Equal(t, SiftProfile{}, heap.SiftProfile())
+}
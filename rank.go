@@ -0,0 +1,129 @@
+package heap
+
+import "time"
+
+// CountLess returns the number of elements that order before x under the less
+// function. Subtrees whose root does not order before x are pruned, so the
+// cost is O(k·d) for k matching elements rather than O(n).
+func (h *Heap[T]) CountLess(x T) int {
+	return h.countWhile(func(v T) bool { return h.lessFunc(v, x) })
+}
+
+// CountLessThan is CountLess under the name that reads naturally in
+// admission checks: how many queued elements beat the candidate x.
+func (h *Heap[T]) CountLessThan(x T) int {
+	return h.CountLess(x)
+}
+
+// CountAtMost returns the number of elements that do not order after x under
+// the less function, i.e. x itself and everything before it. Like CountLess it
+// only visits matching elements and their children.
+func (h *Heap[T]) CountAtMost(x T) int {
+	return h.countWhile(func(v T) bool { return !h.lessFunc(x, v) })
+}
+
+// CountWhere returns the number of elements for which pred returns true. pred
+// may be any predicate, so every element is visited; when it only accepts
+// elements that order before some candidate, CountLess prunes the search.
+func (h *Heap[T]) CountWhere(pred func(T) bool) int {
+	h.ensureHeap()
+	count := 0
+	for _, v := range h.data[:h.heapSize] {
+		if pred(v) {
+			count++
+		}
+	}
+	return count
+}
+
+// countWhile counts the elements satisfying match, which must be closed under
+// the heap order: if an element matches, so must its parent.
+func (h *Heap[T]) countWhile(match func(T) bool) int {
+	h.ensureHeap()
+	if h.heapSize == 0 || !match(h.data[0]) {
+		return 0
+	}
+	count := 0
+	stack := []int{0}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		count++
+		for k := 1; k <= h.d && h.child(i, k) < h.heapSize; k++ {
+			if c := h.child(i, k); match(h.data[c]) {
+				stack = append(stack, c)
+			}
+		}
+	}
+	return count
+}
+
+// Kth returns the k-th element in heap order without modifying the heap,
+// where k = 1 is the element Peek returns, so for a max-heap it is the k-th
+// largest. It explores the tree with an auxiliary heap of positions and runs
+// in O(k log k) time. Expired elements are skipped. If k is out of range, it
+// returns the zero value of type T and false.
+func (h *Heap[T]) Kth(k int) (T, bool) {
+	var kth T
+	found := false
+	if k < 1 {
+		return kth, false
+	}
+	h.walkOrder(func(i int) bool {
+		if k--; k > 0 {
+			return true
+		}
+		kth, found = h.data[i], true
+		return false
+	})
+	return kth, found
+}
+
+// walkOrder calls visit with the position of each live element in heap order,
+// without modifying the heap, until visit returns false. It explores the tree
+// with an auxiliary heap of positions. Expired elements are skipped, though
+// their descendants are still explored.
+func (h *Heap[T]) walkOrder(visit func(i int) bool) {
+	h.ensureHeap()
+	h.ensureTop()
+	if h.heapSize == 0 {
+		return
+	}
+	var now time.Time
+	if h.ttl != nil {
+		now = h.ttl.clock.Now()
+	}
+	frontier := NewHeap[int](h.d, func(i, j int) bool { return h.lessFunc(h.data[i], h.data[j]) }, WithoutIndex[int]())
+	frontier.Push(0)
+	for !frontier.IsEmpty() {
+		i := frontier.Pop()
+		if (h.ttl == nil || !h.ttl.expired(i, now)) && !visit(i) {
+			return
+		}
+		for c := 1; c <= h.d && h.child(i, c) < h.heapSize; c++ {
+			frontier.Push(h.child(i, c))
+		}
+	}
+}
+
+// KthSmallest is Kth under the name that reads naturally for min-heaps.
+func (h *Heap[T]) KthSmallest(k int) (T, bool) {
+	return h.Kth(k)
+}
+
+// Second returns the runner-up: the element Pop would return after the next
+// one. Only the root's d children can hold it, so it runs in O(d) without
+// modifying the heap, unless elements pushed with PushWithTTL may have
+// expired, in which case it is Kth(2). If the heap holds fewer than two
+// elements, it returns the zero value of type T and false.
+func (h *Heap[T]) Second() (T, bool) {
+	if h.ttl != nil {
+		return h.Kth(2)
+	}
+	h.ensureHeap()
+	if h.heapSize < 2 {
+		var zero T
+		return zero, false
+	}
+	return h.data[h.bestChild(0)], true
+}
@@ -0,0 +1,119 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapCountLess(t *testing.T) {
+	tests := []struct {
+		name       string
+		d          int
+		less       func(a, b int) bool
+		x          int
+		wantLess   int
+		wantAtMost int
+	}{
+		{name: "MinHeap middle", d: 2, less: func(a, b int) bool { return a < b }, x: 4, wantLess: 4, wantAtMost: 6},
+		{name: "MinHeap below all", d: 3, less: func(a, b int) bool { return a < b }, x: 0, wantLess: 0, wantAtMost: 0},
+		{name: "MinHeap above all", d: 4, less: func(a, b int) bool { return a < b }, x: 10, wantLess: 12, wantAtMost: 12},
+		{name: "MaxHeap middle", d: 2, less: func(a, b int) bool { return a > b }, x: 4, wantLess: 6, wantAtMost: 8},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			heap := NewHeap[int](tt.d, tt.less)
+			assert.Equal(t, 0, heap.CountLess(tt.x))
+
+			for _, v := range []int{1, 9, 2, 8, 3, 7, 4, 6, 5, 4, 2, 8} {
+				heap.Push(v)
+			}
+			assert.Equal(t, tt.wantLess, heap.CountLess(tt.x), "CountLess(%d)", tt.x)
+			assert.Equal(t, tt.wantLess, heap.CountLessThan(tt.x), "CountLessThan(%d)", tt.x)
+			assert.Equal(t, tt.wantAtMost, heap.CountAtMost(tt.x), "CountAtMost(%d)", tt.x)
+		})
+	}
+}
+
+func TestHeapCountWhere(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b })
+	assert.Zero(t, heap.CountWhere(func(int) bool { return true }))
+	for v := 1; v <= 20; v++ {
+		heap.Push(v)
+	}
+	heap.LazyRemove(4)
+
+	even := func(v int) bool { return v%2 == 0 }
+	assert.Equal(t, 9, heap.CountWhere(even))
+	assert.Equal(t, heap.CountLess(8), heap.CountWhere(func(v int) bool { return v < 8 }))
+	assert.Equal(t, 19, heap.Len(), "CountWhere modified the heap")
+}
+
+func TestHeapKthSmallest(t *testing.T) {
+	values := []int{7, 3, 9, 1, 5, 3, 8, 2, 6, 4}
+	for _, d := range []int{2, 3, 5} {
+		heap := NewHeap[int](d, func(a, b int) bool { return a < b })
+		for _, v := range values {
+			heap.Push(v)
+		}
+
+		for k, want := range []int{1, 2, 3, 3, 4, 5, 6, 7, 8, 9} {
+			got, ok := heap.KthSmallest(k + 1)
+			assert.True(t, ok, "d=%d KthSmallest(%d) returned false", d, k+1)
+			assert.Equal(t, want, got, "d=%d KthSmallest(%d)", d, k+1)
+		}
+		assert.Equal(t, len(values), heap.heapSize, "KthSmallest modified the heap")
+
+		_, ok := heap.KthSmallest(0)
+		assert.False(t, ok)
+		_, ok = heap.KthSmallest(len(values) + 1)
+		assert.False(t, ok)
+	}
+}
+
+func TestHeapKth(t *testing.T) {
+	t.Parallel()
+
+	// For a max-heap the k-th element is the k-th largest.
+	jobs := NewMax[int](4)
+	for v := 1; v <= 50; v++ {
+		jobs.Push(v)
+	}
+	jobs.LazyRemove(50)
+
+	got, ok := jobs.Kth(10)
+	assert.True(t, ok)
+	assert.Equal(t, 40, got)
+	_, ok = jobs.Kth(50)
+	assert.False(t, ok, "deleted elements are not ranked")
+	assert.Equal(t, 49, jobs.Len())
+}
+
+func TestHeapSecond(t *testing.T) {
+	for _, d := range []int{2, 3, 8} {
+		heap := NewHeap[int](d, func(a, b int) bool { return a > b })
+		_, ok := heap.Second()
+		assert.False(t, ok)
+		heap.Push(4)
+		_, ok = heap.Second()
+		assert.False(t, ok)
+
+		for _, v := range []int{7, 1, 9, 7, 3} {
+			heap.Push(v)
+		}
+		for _, want := range []int{7, 7, 4, 3, 1} {
+			got, ok := heap.Second()
+			assert.True(t, ok)
+			assert.Equal(t, want, got, "d=%d", d)
+			heap.Pop()
+		}
+		_, ok = heap.Second()
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,121 @@
+package heap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Scheduler runs callbacks at scheduled times from a single goroutine. It
+// orders pending callbacks in a heap and waits for the earliest with one
+// time.Timer, so thousands of pending callbacks cost one timer rather than
+// one time.AfterFunc each.
+type Scheduler struct {
+	mu      sync.Mutex
+	heap    *Heap[*scheduledTask]
+	seq     uint64        // Sequence number of the last scheduled callback
+	changed chan struct{} // Wakes Run when the earliest callback changes
+}
+
+// scheduledTask is a callback waiting in a Scheduler.
+type scheduledTask struct {
+	at  time.Time
+	seq uint64
+	fn  func()
+}
+
+// Handle identifies a callback scheduled with Scheduler.Schedule so it can be
+// canceled. The zero Handle refers to no callback.
+type Handle struct {
+	item *Item[*scheduledTask]
+}
+
+// NewScheduler creates a scheduler with no pending callbacks.
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		heap: NewHeap[*scheduledTask](4, func(a, b *scheduledTask) bool {
+			if !a.at.Equal(b.at) {
+				return a.at.Before(b.at)
+			}
+			return a.seq < b.seq
+		}, WithoutIndex[*scheduledTask]()),
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// Len returns the number of callbacks waiting to run.
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Len()
+}
+
+// Schedule arranges for fn to be called by Run at the given time, or as soon
+// as possible if it has passed. Callbacks due at the same time run in the
+// order they were scheduled.
+func (s *Scheduler) Schedule(at time.Time, fn func()) Handle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	task := &scheduledTask{at: at, seq: s.seq, fn: fn}
+	item := s.heap.PushHandle(task)
+	if s.heap.Peek() == task {
+		select {
+		case s.changed <- struct{}{}:
+		default: // A wake-up is already pending
+		}
+	}
+	return Handle{item: item}
+}
+
+// Cancel stops the callback h refers to from running. It returns false if the
+// callback has already started or been canceled.
+func (s *Scheduler) Cancel(h Handle) bool {
+	if h.item == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveHandle(h.item)
+}
+
+// Run calls scheduled callbacks in time order as they fall due until ctx is
+// done, then returns ctx's error. ctx is checked between callbacks, so a burst
+// of due callbacks stops as soon as it is done. Callbacks run on Run's
+// goroutine and may schedule or cancel other callbacks. Run must not be
+// called concurrently with itself.
+func (s *Scheduler) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		var (
+			due  *scheduledTask
+			wait <-chan time.Time
+		)
+		if s.heap.Len() > 0 {
+			if d := time.Until(s.heap.Peek().at); d <= 0 {
+				due = s.heap.Pop()
+			} else {
+				timer.Reset(d)
+				wait = timer.C
+			}
+		}
+		s.mu.Unlock()
+
+		if due != nil {
+			due.fn()
+			continue
+		}
+		select {
+		case <-wait:
+		case <-s.changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package heap
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	s := NewScheduler()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	var (
+		mu  sync.Mutex
+		ran []int
+	)
+	record := func(i int) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			ran = append(ran, i)
+		}
+	}
+
+	now := time.Now()
+	all := make(chan struct{})
+	s.Schedule(now.Add(40*time.Millisecond), func() { record(4)(); close(all) })
+	s.Schedule(now.Add(20*time.Millisecond), record(2))
+	s.Schedule(now.Add(20*time.Millisecond), record(3))
+	canceled := s.Schedule(now.Add(30*time.Millisecond), record(-1))
+	s.Schedule(now.Add(-time.Second), record(1))
+	assert.True(t, s.Cancel(canceled))
+	assert.False(t, s.Cancel(canceled), "already canceled")
+	assert.False(t, s.Cancel(Handle{}))
+
+	select {
+	case <-all:
+	case <-time.After(5 * time.Second):
+		t.Fatal("callbacks did not run")
+	}
+	mu.Lock()
+	assert.Equal(t, []int{1, 2, 3, 4}, ran)
+	mu.Unlock()
+	assert.Zero(t, s.Len())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestSchedulerEarlierCallback(t *testing.T) {
+	t.Parallel()
+
+	s := NewScheduler()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	late := s.Schedule(time.Now().Add(time.Hour), func() {})
+	time.Sleep(10 * time.Millisecond)
+	fired := make(chan struct{})
+	handle := make(chan Handle, 1)
+	handle <- s.Schedule(time.Now().Add(10*time.Millisecond), func() {
+		assert.False(t, s.Cancel(<-handle), "a running callback cannot be canceled")
+		close(fired)
+	})
+
+	select {
+	case <-fired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not re-arm its timer for the earlier callback")
+	}
+	assert.Equal(t, 1, s.Len())
+	assert.True(t, s.Cancel(late))
+}
+
+func TestSchedulerCanceledDuringBurst(t *testing.T) {
+	t.Parallel()
+
+	s := NewScheduler()
+	ctx, cancel := context.WithCancel(context.Background())
+	past := time.Now().Add(-time.Second)
+	ran := 0
+	s.Schedule(past, func() { ran++; cancel() })
+	for i := 0; i < 3; i++ {
+		s.Schedule(past, func() { ran++ })
+	}
+
+	assert.ErrorIs(t, s.Run(ctx), context.Canceled)
+	assert.Equal(t, 1, ran, "callbacks ran after ctx was done")
+	assert.Equal(t, 3, s.Len())
+}
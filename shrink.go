@@ -0,0 +1,111 @@
+package heap
+
+import "time"
+
+// shrinkPolicy tracks how long the heap has stayed below its shrink threshold.
+type shrinkPolicy struct {
+	threshold float64 // Fraction of capacity below which the heap counts as underused
+	window    int     // Consecutive underused operations before shrinking
+	low       int     // Consecutive underused operations so far
+	floor     int     // Capacity the heap was created with, never shrunk below
+}
+
+// WithAutoShrink is an option that compacts the heap's backing array and index
+// once its size has stayed below threshold times its capacity for window
+// consecutive pushes and pops. The new capacity is twice the current size, so
+// a long-lived queue gives back the memory of a past peak without shrinking
+// again on the next small fluctuation. The heap never shrinks below the
+// capacity it was created with.
+func WithAutoShrink[T comparable](threshold float64, window int) Option[T] {
+	return func(h *Heap[T]) {
+		h.shrink = &shrinkPolicy{threshold: threshold, window: window}
+	}
+}
+
+// WithShrinkFactor is an option that compacts the heap's backing array and
+// index as soon as its size drops below 1/f of its capacity, leaving it half
+// full. It is WithAutoShrink with no window, for heaps that should give back
+// memory right after a large drain. f must be greater than 1; the heap never
+// shrinks below the capacity it was created with.
+func WithShrinkFactor[T comparable](f float64) Option[T] {
+	return WithAutoShrink[T](1/f, 1)
+}
+
+// Compact reallocates the heap's backing array to fit its current elements
+// and rebuilds its index in a map sized for them, releasing the memory held
+// from a past peak. Unlike automatic shrinking it ignores the capacity the
+// heap was created with, though it keeps room for at least 16 elements.
+func (h *Heap[T]) Compact() {
+	h.ensureHeap()
+	h.shrinkTo(h.heapSize)
+}
+
+// Cap returns the number of elements the heap can hold before it must grow
+// its backing array.
+func (h *Heap[T]) Cap() int {
+	return cap(h.data)
+}
+
+// checkShrink counts an operation against the shrink policy and compacts the
+// heap when it has been underused for the whole window.
+func (h *Heap[T]) checkShrink() {
+	s := h.shrink
+	if float64(h.heapSize) >= s.threshold*float64(cap(h.data)) {
+		s.low = 0
+		return
+	}
+	s.low++
+	if s.low >= s.window {
+		s.low = 0
+		h.shrinkTo(max(2*h.heapSize, s.floor))
+	}
+}
+
+// shrinkTo reallocates the backing array with the given capacity, which must
+// be at least the heap size, and rebuilds the index in a map sized for the
+// current contents. Go maps never release buckets, so the index is copied
+// rather than cleared.
+func (h *Heap[T]) shrinkTo(capacity int) {
+	const minCapacity = 16
+	capacity = max(capacity, h.heapSize, minCapacity)
+	if capacity >= cap(h.data) {
+		return
+	}
+	data := make([]T, h.heapSize, capacity)
+	copy(data, h.data[:h.heapSize])
+	h.data = data
+	if h.wait != nil {
+		enqueued := make([]time.Time, h.heapSize, capacity)
+		copy(enqueued, h.wait.enqueued[:h.heapSize])
+		h.wait.enqueued = enqueued
+	}
+	if h.items != nil {
+		items := make([]*Item[T], h.heapSize, capacity)
+		copy(items, h.items[:min(h.heapSize, len(h.items))])
+		h.items = items
+	}
+	if h.ttl != nil {
+		expires := make([]time.Time, h.heapSize, capacity)
+		copy(expires, h.ttl.expires[:h.heapSize])
+		h.ttl.expires = expires
+	}
+	switch {
+	case h.noIndex:
+	case h.keyed != nil:
+		h.keyed.compact()
+	case h.hash != nil:
+		h.hashed = compactIndex(h.hashed)
+	default:
+		h.index = compactIndex(h.index)
+	}
+	h.spare = nil
+}
+
+// compactIndex copies index into a new map sized for its current entries.
+func compactIndex[K comparable](index map[K][]int) map[K][]int {
+	compacted := make(map[K][]int, len(index))
+	for k, indices := range index {
+		compacted[k] = indices
+	}
+	return compacted
+}
@@ -0,0 +1,97 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoShrink(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithAutoShrink[int](0.25, 10))
+	for i := 0; i < 1000; i++ {
+		heap.Push(i)
+	}
+	peak := heap.Cap()
+	assert.GreaterOrEqual(t, peak, 1000)
+
+	// Draining to below a quarter of capacity starts the window; the heap
+	// shrinks once it has stayed there for 10 operations.
+	for i := 0; i < 1000-100; i++ {
+		heap.Pop()
+	}
+	assert.Less(t, heap.Cap(), peak)
+	assert.GreaterOrEqual(t, heap.Cap(), heap.heapSize)
+
+	for want := 900; want < 1000; want++ {
+		assert.True(t, heap.Contains(want))
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.Empty(t, heap.index)
+}
+
+func TestAutoShrinkWindowResets(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b }, WithCapacity[int](100), WithAutoShrink[int](0.5, 5))
+	assert.Equal(t, 100, heap.Cap())
+	for i := 0; i < 60; i++ {
+		heap.Push(i)
+	}
+	for round := 0; round < 20; round++ {
+		// Each dip stays below half the capacity for only three operations
+		// in a row before pushes bring it back above the threshold.
+		for i := 0; i < 12; i++ {
+			heap.Pop()
+		}
+		for i := 0; i < 12; i++ {
+			heap.Push(100 + i)
+		}
+	}
+	assert.Equal(t, 100, heap.Cap(), "shrunk although the window never completed")
+}
+
+func TestShrinkFactor(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithShrinkFactor[int](4))
+	for i := 0; i < 1024; i++ {
+		heap.Push(i)
+	}
+	peak := heap.Cap()
+	for i := 0; i < 1024-peak/4; i++ {
+		heap.Pop()
+	}
+	assert.Equal(t, peak, heap.Cap(), "a quarter full is not below the factor")
+	heap.Pop()
+	assert.Less(t, heap.Cap(), peak)
+	assert.GreaterOrEqual(t, heap.Cap(), 2*heap.Len())
+	assert.NoError(t, heap.Verify())
+}
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b }, WithCapacity[int](4096))
+	var items []*Item[int]
+	for i := 0; i < 4000; i++ {
+		items = append(items, heap.PushHandle(i))
+	}
+	for i := 0; i < 3950; i++ {
+		heap.Pop()
+	}
+	heap.LazyRemove(3999)
+
+	heap.Compact()
+	assert.Equal(t, 49, heap.Len())
+	assert.Equal(t, 49, heap.Cap())
+	assert.NoError(t, heap.Verify())
+	assert.True(t, heap.RemoveHandle(items[3998]), "handles survive compaction")
+	assert.False(t, heap.Contains(3999))
+	assert.Equal(t, 3950, heap.Pop())
+
+	empty := NewHeap[int](2, func(a, b int) bool { return a < b }, WithCapacity[int](1000))
+	empty.Compact()
+	assert.Equal(t, 16, empty.Cap())
+}
@@ -0,0 +1,139 @@
+package heap
+
+// SiftStrategy selects the algorithm used to move an element down the heap
+// after the root is removed.
+type SiftStrategy int
+
+const (
+	// SiftStandard compares the element against its children at every level
+	// and stops as soon as the heap property holds.
+	SiftStandard SiftStrategy = iota
+	// SiftBottomUp first follows the path of best children down to a leaf
+	// without looking at the element, then climbs back up to the position
+	// where the element belongs. Because an element moved down from the root
+	// usually belongs near the bottom, this saves roughly one comparison per
+	// level, which pays off for expensive comparators and large d.
+	SiftBottomUp
+)
+
+// WithSiftStrategy is an option that sets the sift-down strategy of the heap.
+func WithSiftStrategy[T comparable](strategy SiftStrategy) Option[T] {
+	return func(h *Heap[T]) {
+		h.strategy = strategy
+	}
+}
+
+// bestChild returns the index of the child of i that orders first, or -1 if i
+// is a leaf.
+func (h *Heap[T]) bestChild(i int) int {
+	first := h.child(i, 1)
+	if first >= h.heapSize {
+		return -1
+	}
+	best := first
+	for c := first + 1; c < first+h.d && c < h.heapSize; c++ {
+		if h.lessFunc(h.data[c], h.data[best]) {
+			best = c
+		}
+	}
+	return best
+}
+
+// downBottomUp restores the heap property below i using the bottom-up strategy.
+// It returns the number of levels the element moved.
+func (h *Heap[T]) downBottomUp(i int) int {
+	// Descend along the best children to a leaf, remembering the path.
+	path := append(h.path[:0], i)
+	for c := h.bestChild(i); c >= 0; c = h.bestChild(c) {
+		path = append(path, c)
+	}
+
+	// Climb back up to the deepest position whose element orders before ours.
+	x := h.data[i]
+	end := len(path) - 1
+	for end > 0 && h.lessFunc(x, h.data[path[end]]) {
+		end--
+	}
+
+	// Shift the path up by one level and drop the element into place.
+	for k := 0; k < end; k++ {
+		h.swap(path[k], path[k+1])
+	}
+	h.path = path
+	return end
+}
+
+// minChildLoop returns the index among i and its children whose element
+// orders first, preferring the earliest on ties.
+func (h *Heap[T]) minChildLoop(i int) int {
+	smallest := i // Assume the current node is the smallest
+	for k := 1; k <= h.d && h.child(i, k) < h.heapSize; k++ {
+		childIndex := h.child(i, k)
+		if h.lessFunc(h.data[childIndex], h.data[smallest]) {
+			smallest = childIndex
+		}
+	}
+	return smallest
+}
+
+// minChild2, minChild3 and minChild4 are minChildLoop unrolled for d = 2, 3
+// and 4. Nodes with a full set of children are compared without a loop and
+// with a single bounds check; the last internal node falls back to the loop.
+
+func (h *Heap[T]) minChild2(i int) int {
+	first := h.child(i, 1)
+	if first+2 > h.heapSize {
+		return h.minChildLoop(i)
+	}
+	c := h.data[first : first+2 : first+2]
+	best := 0
+	if h.lessFunc(c[1], c[0]) {
+		best = 1
+	}
+	if h.lessFunc(c[best], h.data[i]) {
+		return first + best
+	}
+	return i
+}
+
+func (h *Heap[T]) minChild3(i int) int {
+	first := h.child(i, 1)
+	if first+3 > h.heapSize {
+		return h.minChildLoop(i)
+	}
+	c := h.data[first : first+3 : first+3]
+	best := 0
+	if h.lessFunc(c[1], c[0]) {
+		best = 1
+	}
+	if h.lessFunc(c[2], c[best]) {
+		best = 2
+	}
+	if h.lessFunc(c[best], h.data[i]) {
+		return first + best
+	}
+	return i
+}
+
+func (h *Heap[T]) minChild4(i int) int {
+	first := h.child(i, 1)
+	if first+4 > h.heapSize {
+		return h.minChildLoop(i)
+	}
+	c := h.data[first : first+4 : first+4]
+	lo, hi := 0, 2
+	if h.lessFunc(c[1], c[0]) {
+		lo = 1
+	}
+	if h.lessFunc(c[3], c[2]) {
+		hi = 3
+	}
+	best := lo
+	if h.lessFunc(c[hi], c[lo]) {
+		best = hi
+	}
+	if h.lessFunc(c[best], h.data[i]) {
+		return first + best
+	}
+	return i
+}
@@ -0,0 +1,91 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSiftBottomUp(t *testing.T) {
+	for _, d := range []int{2, 3, 4, 8} {
+		rng := rand.New(rand.NewSource(int64(d)))
+		values := make([]int, 500)
+		for i := range values {
+			values[i] = rng.Intn(100)
+		}
+
+		heap := NewHeap[int](d, func(a, b int) bool { return a < b }, WithSiftStrategy[int](SiftBottomUp))
+		for _, v := range values {
+			heap.Push(v)
+		}
+
+		sort.Ints(values)
+		for _, want := range values {
+			assert.Equal(t, want, heap.Pop(), "d=%d", d)
+		}
+		assert.Empty(t, heap.index, "d=%d index not empty after draining", d)
+	}
+}
+
+func benchmarkSift(b *testing.B, d int, strategy SiftStrategy) {
+	const n = 1 << 14
+	rng := rand.New(rand.NewSource(1))
+	comparisons := 0
+	less := func(a, b int) bool {
+		comparisons++
+		return a < b
+	}
+	heap := NewHeap[int](d, less, WithSiftStrategy[int](strategy))
+	for i := 0; i < n; i++ {
+		heap.Push(rng.Int())
+	}
+
+	comparisons = 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(heap.Pop() + rng.Intn(n))
+	}
+	b.ReportMetric(float64(comparisons)/float64(b.N), "cmps/op")
+}
+
+func BenchmarkSiftStandardD2(b *testing.B) { benchmarkSift(b, 2, SiftStandard) }
+func BenchmarkSiftBottomUpD2(b *testing.B) { benchmarkSift(b, 2, SiftBottomUp) }
+func BenchmarkSiftStandardD4(b *testing.B) { benchmarkSift(b, 4, SiftStandard) }
+func BenchmarkSiftBottomUpD4(b *testing.B) { benchmarkSift(b, 4, SiftBottomUp) }
+func BenchmarkSiftStandardD8(b *testing.B) { benchmarkSift(b, 8, SiftStandard) }
+func BenchmarkSiftBottomUpD8(b *testing.B) { benchmarkSift(b, 8, SiftBottomUp) }
+
+func TestMinChildUnrolled(t *testing.T) {
+	for _, d := range []int{2, 3, 4} {
+		rng := rand.New(rand.NewSource(int64(d)))
+		heap := NewHeap[int](d, func(a, b int) bool { return a < b })
+		assert.NotNil(t, heap.minChild, "d=%d", d)
+		for n := 0; n < 60; n++ {
+			heap.data = heap.data[:0]
+			for i := 0; i < n; i++ {
+				heap.data = append(heap.data, rng.Intn(5)) // Small range to exercise ties
+			}
+			heap.heapSize = n
+			for i := 0; i < n; i++ {
+				assert.Equal(t, heap.minChildLoop(i), heap.minChild(heap, i), "d=%d n=%d i=%d", d, n, i)
+			}
+		}
+	}
+}
+
+func benchmarkDown(b *testing.B, d int, unrolled bool) {
+	heap := NewHeap[int](d, func(a, b int) bool { return a < b })
+	if !unrolled {
+		heap.minChild = nil
+	}
+	benchmarkHeapPop(b, heap)
+}
+
+func BenchmarkDownUnrolledD2(b *testing.B) { benchmarkDown(b, 2, true) }
+func BenchmarkDownLoopD2(b *testing.B)     { benchmarkDown(b, 2, false) }
+func BenchmarkDownUnrolledD3(b *testing.B) { benchmarkDown(b, 3, true) }
+func BenchmarkDownLoopD3(b *testing.B)     { benchmarkDown(b, 3, false) }
+func BenchmarkDownUnrolledD4(b *testing.B) { benchmarkDown(b, 4, true) }
+func BenchmarkDownLoopD4(b *testing.B)     { benchmarkDown(b, 4, false) }
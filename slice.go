@@ -0,0 +1,113 @@
+package heap
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// checkBranching panics with an error wrapping ErrInvalidBranchingFactor if d
+// is below 2, for NewAnyHeap and the slice functions, which have no error to
+// return.
+func checkBranching(d int) {
+	if d < 2 {
+		panic(fmt.Errorf("%w: must be at least 2, got %d", ErrInvalidBranchingFactor, d))
+	}
+}
+
+// heapifySlice arranges s into a d-ary heap ordered by less in O(n) time using
+// Floyd's bottom-up construction.
+func heapifySlice[T any](s []T, d int, less func(T, T) bool) {
+	if len(s) < 2 {
+		return
+	}
+	for i := (len(s) - 2) / d; i >= 0; i-- {
+		siftDownSlice(s, i, len(s), d, less)
+	}
+}
+
+// siftDownSlice moves s[i] down the d-ary heap stored in s[:n] until the heap
+// property holds.
+func siftDownSlice[T any](s []T, i, n, d int, less func(T, T) bool) {
+	for {
+		best := i
+		first := d*i + 1
+		for c := first; c < first+d && c < n; c++ {
+			if less(s[c], s[best]) {
+				best = c
+			}
+		}
+		if best == i {
+			return
+		}
+		s[i], s[best] = s[best], s[i]
+		i = best
+	}
+}
+
+// popSlice moves the root of the d-ary heap in s[:n] to s[n-1] and restores
+// the heap property over s[:n-1].
+func popSlice[T any](s []T, n, d int, less func(T, T) bool) {
+	s[0], s[n-1] = s[n-1], s[0]
+	siftDownSlice(s, 0, n-1, d, less)
+}
+
+// Sort sorts s in place so that no element orders before its predecessor
+// under less, using a d-ary heapsort. It runs in O(n·d·log_d n) time without
+// allocating and is not stable. It panics if d is less than 2.
+func Sort[T any](d int, less func(T, T) bool, s []T) {
+	checkBranching(d)
+	greater := func(a, b T) bool { return less(b, a) }
+	heapifySlice(s, d, greater)
+	for n := len(s); n > 1; n-- {
+		popSlice(s, n, d, greater)
+	}
+}
+
+// Select returns the k elements of s that order first under less, in order.
+// It copies s, heapifies the copy once in O(n) and extracts k elements in
+// O(k·d·log_d n), which beats a full sort when k is much smaller than len(s).
+// If k exceeds len(s), all elements are returned in order. s is not modified.
+// It panics if d is less than 2.
+func Select[T any](s []T, k, d int, less func(T, T) bool) []T {
+	checkBranching(d)
+	if k > len(s) {
+		k = len(s)
+	}
+	if k <= 0 {
+		return []T{}
+	}
+
+	work := append([]T(nil), s...)
+	heapifySlice(work, d, less)
+	result := make([]T, k)
+	for i := range result {
+		result[i] = work[0]
+		popSlice(work, len(work)-i, d, less)
+	}
+	return result
+}
+
+// IncrementalSort returns an iterator over the elements of s in ascending
+// order. The work is done lazily: the first element costs an O(n) heapify of a
+// copy of s and each further element one O(log n) extraction, so consumers that
+// stop early never pay for a full sort. s is not modified.
+func IncrementalSort[T cmp.Ordered](s []T) iter.Seq[T] {
+	return IncrementalSortFunc(s, 4, func(a, b T) bool { return a < b })
+}
+
+// IncrementalSortFunc is like IncrementalSort but orders elements by less using
+// a heap with branching factor d. It panics if d is less than 2.
+func IncrementalSortFunc[T any](s []T, d int, less func(T, T) bool) iter.Seq[T] {
+	checkBranching(d)
+	return func(yield func(T) bool) {
+		work := append([]T(nil), s...)
+		heapifySlice(work, d, less)
+		for n := len(work); n > 0; n-- {
+			if !yield(work[0]) {
+				return
+			}
+			popSlice(work, n, d, less)
+		}
+	}
+}
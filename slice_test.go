@@ -0,0 +1,113 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelect(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = rng.Intn(500)
+	}
+	original := append([]int(nil), values...)
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	tests := []struct {
+		name string
+		k    int
+		d    int
+		want []int
+	}{
+		{name: "Top 10 binary", k: 10, d: 2, want: sorted[:10]},
+		{name: "Top 100 d=4", k: 100, d: 4, want: sorted[:100]},
+		{name: "All", k: len(values), d: 3, want: sorted},
+		{name: "More than length", k: 2 * len(values), d: 3, want: sorted},
+		{name: "Zero", k: 0, d: 2, want: []int{}},
+		{name: "Negative", k: -1, d: 2, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Select(values, tt.k, tt.d, func(a, b int) bool { return a < b }))
+		})
+	}
+
+	t.Run("Largest", func(t *testing.T) {
+		got := Select(values, 3, 2, func(a, b int) bool { return a > b })
+		assert.Equal(t, []int{sorted[len(sorted)-1], sorted[len(sorted)-2], sorted[len(sorted)-3]}, got)
+	})
+	assert.Equal(t, original, values, "Select modified its input")
+}
+
+func TestIncrementalSort(t *testing.T) {
+	values := []int{5, 1, 4, 1, 5, 9, 2, 6, 5, 3}
+
+	var got []int
+	for v := range IncrementalSort(values) {
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{1, 1, 2, 3, 4, 5, 5, 5, 6, 9}, got)
+	assert.Equal(t, []int{5, 1, 4, 1, 5, 9, 2, 6, 5, 3}, values, "IncrementalSort modified its input")
+
+	got = got[:0]
+	for v := range IncrementalSortFunc(values, 3, func(a, b int) bool { return a > b }) {
+		if len(got) == 3 {
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{9, 6, 5}, got)
+
+	for range IncrementalSort([]int{}) {
+		t.Fatal("IncrementalSort of an empty slice yielded an element")
+	}
+}
+
+func TestSort(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, n := range []int{0, 1, 2, 17, 1000} {
+		for _, d := range []int{2, 3, 4, 8} {
+			values := make([]int, n)
+			for i := range values {
+				values[i] = rng.Intn(100)
+			}
+			want := append([]int{}, values...)
+			sort.Ints(want)
+
+			Sort(d, func(a, b int) bool { return a < b }, values)
+			assert.Equal(t, want, values, "n=%d d=%d", n, d)
+		}
+	}
+
+	descending := []string{"b", "d", "a", "c"}
+	Sort(2, func(a, b string) bool { return a > b }, descending)
+	assert.Equal(t, []string{"d", "c", "b", "a"}, descending)
+
+	values := []int{5, 2, 9, 1}
+	allocs := testing.AllocsPerRun(10, func() { Sort(4, func(a, b int) bool { return a < b }, values) })
+	assert.Zero(t, allocs)
+}
+
+func TestSliceInvalidBranchingFactor(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	for _, d := range []int{-1, 0, 1} {
+		invalid := func(fn func()) {
+			defer func() {
+				err, _ := recover().(error)
+				assert.ErrorIs(t, err, ErrInvalidBranchingFactor, "d=%d", d)
+			}()
+			fn()
+		}
+		invalid(func() { Sort(d, less, []int{3, 1, 2}) })
+		invalid(func() { Select([]int{3, 1, 2}, 2, d, less) })
+		invalid(func() { IncrementalSortFunc([]int{3, 1, 2}, d, less) })
+	}
+}
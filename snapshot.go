@@ -0,0 +1,222 @@
+package heap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// snapshotMagic starts every binary snapshot written by WriteTo.
+const snapshotMagic = "DHP1"
+
+// maxSnapshotBranching is the largest branching factor ReadFrom accepts, so a
+// corrupt header cannot make index arithmetic overflow.
+const maxSnapshotBranching = 1 << 16
+
+// WriteTo writes a compact binary snapshot of the heap to w and returns the
+// number of bytes written, implementing io.WriterTo. The snapshot holds the
+// branching factor and the elements in heap order: integers as varints,
+// floats as fixed-width little-endian values and strings length-prefixed.
+// The comparator and options are not written. Elements of any other kind,
+// such as structs or pointers, cannot be written and WriteTo returns
+// ErrUnsupportedKind without writing anything.
+func (h *Heap[T]) WriteTo(w io.Writer) (int64, error) {
+	var zero T
+	kind := reflect.ValueOf(zero).Kind()
+	if !snapshotKind(kind) {
+		return 0, fmt.Errorf("%w: %v", ErrUnsupportedKind, kind)
+	}
+	h.ensureHeap()
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	var scratch [binary.MaxVarintLen64]byte
+	putUvarint := func(x uint64) {
+		bw.Write(scratch[:binary.PutUvarint(scratch[:], x)])
+	}
+	bw.WriteString(snapshotMagic)
+	putUvarint(uint64(kind))
+	putUvarint(uint64(h.d))
+	putUvarint(uint64(h.heapSize))
+	for _, v := range h.data[:h.heapSize] {
+		rv := reflect.ValueOf(v)
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			bw.Write(scratch[:binary.PutVarint(scratch[:], rv.Int())])
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			putUvarint(rv.Uint())
+		case reflect.Float32:
+			bw.Write(binary.LittleEndian.AppendUint32(scratch[:0], math.Float32bits(float32(rv.Float()))))
+		case reflect.Float64:
+			bw.Write(binary.LittleEndian.AppendUint64(scratch[:0], math.Float64bits(rv.Float())))
+		case reflect.String:
+			s := rv.String()
+			putUvarint(uint64(len(s)))
+			bw.WriteString(s)
+		}
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ReadFrom replaces the contents of the heap with a snapshot written by
+// WriteTo and returns the number of bytes read, implementing io.ReaderFrom.
+// The heap adopts the snapshot's branching factor and keeps its own
+// comparator and options. It returns ErrBadSnapshot if the snapshot is
+// malformed, was written by a heap of a different element kind, holds
+// elements of a kind WriteTo cannot write or has a branching factor outside
+// [2, 65536]. ReadFrom never reads past the end of the snapshot, so further
+// data can follow it on the same stream. If r is not an io.ByteReader, varints
+// are read a byte at a time; wrap r in a bufio.Reader to avoid that.
+func (h *Heap[T]) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	cr.br, _ = r.(io.ByteReader)
+	var zero T
+	typ := reflect.TypeOf(zero)
+
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(cr, magic); err != nil {
+		return cr.n, err
+	}
+	if string(magic) != snapshotMagic {
+		return cr.n, fmt.Errorf("%w: missing header", ErrBadSnapshot)
+	}
+	kind, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return cr.n, err
+	}
+	if reflect.Kind(kind) != typ.Kind() {
+		return cr.n, fmt.Errorf("%w: elements are %v, not %v", ErrBadSnapshot, reflect.Kind(kind), typ.Kind())
+	}
+	if !snapshotKind(typ.Kind()) {
+		return cr.n, fmt.Errorf("%w: %v elements are not supported", ErrBadSnapshot, typ.Kind())
+	}
+	d, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return cr.n, err
+	}
+	if d < 2 || d > maxSnapshotBranching {
+		return cr.n, fmt.Errorf("%w: branching factor %d", ErrBadSnapshot, d)
+	}
+	n, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return cr.n, err
+	}
+
+	elements := make([]T, 0, min(n, 1<<16)) // Do not trust n for the allocation
+	var fixed [8]byte
+	for i := uint64(0); i < n; i++ {
+		v := reflect.New(typ).Elem()
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var x int64
+			if x, err = binary.ReadVarint(cr); err == nil {
+				v.SetInt(x)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			var x uint64
+			if x, err = binary.ReadUvarint(cr); err == nil {
+				v.SetUint(x)
+			}
+		case reflect.Float32:
+			if _, err = io.ReadFull(cr, fixed[:4]); err == nil {
+				v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(fixed[:4]))))
+			}
+		case reflect.Float64:
+			if _, err = io.ReadFull(cr, fixed[:8]); err == nil {
+				v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(fixed[:8])))
+			}
+		case reflect.String:
+			var size uint64
+			if size, err = binary.ReadUvarint(cr); err == nil {
+				var s string
+				if s, err = readString(cr, size); err == nil {
+					v.SetString(s)
+				}
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return cr.n, err
+		}
+		elements = append(elements, v.Interface().(T))
+	}
+	return cr.n, h.restore(int(d), elements)
+}
+
+// snapshotKind reports whether elements of the given kind can be written to
+// a snapshot.
+func snapshotKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+// readString reads a string of size bytes. The size comes from the input, so
+// the string is read in bounded chunks and memory grows only with the bytes
+// actually present; a size the input cannot back is ErrBadSnapshot.
+func readString(r io.Reader, size uint64) (string, error) {
+	if size > math.MaxInt32 {
+		return "", fmt.Errorf("%w: string of %d bytes", ErrBadSnapshot, size)
+	}
+	var b strings.Builder
+	if n, err := io.CopyN(&b, r, int64(size)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", fmt.Errorf("%w: string of %d bytes ends after %d: %w", ErrBadSnapshot, size, n, err)
+	}
+	return b.String(), nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read through it. It reads single bytes
+// through br when r implements io.ByteReader and with one-byte reads from r
+// otherwise, so it never buffers past what it returns.
+type countingReader struct {
+	r   io.Reader
+	br  io.ByteReader
+	n   int64
+	one [1]byte
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	if c.br != nil {
+		b, err := c.br.ReadByte()
+		if err == nil {
+			c.n++
+		}
+		return b, err
+	}
+	if _, err := io.ReadFull(c, c.one[:]); err != nil {
+		return 0, err
+	}
+	return c.one[0], nil
+}
@@ -0,0 +1,173 @@
+package heap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type priority int16
+
+func testSnapshotRoundTrip[T ~int | ~int16 | ~uint8 | ~float32 | ~float64 | ~string](t *testing.T, values []T) {
+	t.Helper()
+
+	less := func(a, b T) bool { return a < b }
+	heap := NewHeap[T](3, less)
+	for _, v := range values {
+		heap.Push(v)
+	}
+	want := heap.ToSortedSlice()
+
+	var buf bytes.Buffer
+	written, err := heap.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), written)
+
+	restored := NewHeap[T](2, less)
+	read, err := restored.ReadFrom(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, written, read)
+	assert.Equal(t, 3, restored.BranchingFactor())
+	assert.Equal(t, want, restored.PopN(len(values)))
+	assert.Empty(t, restored.index)
+}
+
+func TestHeapSnapshot(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(1))
+	ints := make([]int, 1000)
+	for i := range ints {
+		ints[i] = rng.Intn(1<<40) - 1<<39
+	}
+	testSnapshotRoundTrip(t, ints)
+	testSnapshotRoundTrip(t, []priority{-3, 7, 0, -32768, 32767})
+	testSnapshotRoundTrip(t, []uint8{200, 3, 255, 0})
+	testSnapshotRoundTrip(t, []float32{1.5, -2.25, 0})
+	testSnapshotRoundTrip(t, []float64{3.14, -1e300, 2.5e-10})
+	testSnapshotRoundTrip(t, []string{"pear", "", "apple", "日本"})
+	testSnapshotRoundTrip(t, []int{})
+}
+
+func TestHeapReadFromErrors(t *testing.T) {
+	t.Parallel()
+
+	strings := NewHeap[string](2, func(a, b string) bool { return a < b })
+	strings.Push("a")
+	var encoded bytes.Buffer
+	_, err := strings.WriteTo(&encoded)
+	assert.NoError(t, err)
+	truncated := encoded.Bytes()[:encoded.Len()-1]
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{name: "empty", data: nil, want: io.EOF},
+		{name: "not a snapshot", data: []byte("JSON{}"), want: ErrBadSnapshot},
+		{name: "wrong kind", data: encoded.Bytes(), want: ErrBadSnapshot},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+			_, err := heap.ReadFrom(bytes.NewReader(tt.data))
+			assert.True(t, errors.Is(err, tt.want), "got %v, want %v", err, tt.want)
+		})
+	}
+
+	_, err = NewHeap[string](2, func(a, b string) bool { return a < b }).ReadFrom(bytes.NewReader(truncated))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestHeapSnapshotUnsupportedKind(t *testing.T) {
+	t.Parallel()
+
+	type job struct{ priority int }
+	heap := NewHeap[job](2, func(a, b job) bool { return a.priority < b.priority })
+	heap.Push(job{1})
+	var buf bytes.Buffer
+	n, err := heap.WriteTo(&buf)
+	assert.ErrorIs(t, err, ErrUnsupportedKind)
+	assert.Zero(t, n)
+	assert.Zero(t, buf.Len())
+
+	// A snapshot claiming struct elements is rejected rather than read back
+	// as zero values.
+	crafted := binary.AppendUvarint([]byte(snapshotMagic), uint64(reflect.Struct))
+	crafted = binary.AppendUvarint(crafted, 2)
+	crafted = binary.AppendUvarint(crafted, 3)
+	_, err = heap.ReadFrom(bytes.NewReader(crafted))
+	assert.ErrorIs(t, err, ErrBadSnapshot)
+	assert.Equal(t, 1, heap.Len(), "a rejected snapshot replaced the contents")
+}
+
+func TestHeapReadFromStringLength(t *testing.T) {
+	t.Parallel()
+
+	header := binary.AppendUvarint([]byte(snapshotMagic), uint64(reflect.String))
+	header = binary.AppendUvarint(header, 2)
+	header = binary.AppendUvarint(header, 1)
+
+	for _, size := range []uint64{1 << 62, math.MaxUint64, 1 << 20} {
+		data := binary.AppendUvarint(append([]byte(nil), header...), size)
+		data = append(data, "short"...)
+		heap := NewHeap[string](2, func(a, b string) bool { return a < b })
+		_, err := heap.ReadFrom(bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrBadSnapshot, "length %d", size)
+		assert.Zero(t, heap.Len())
+	}
+}
+
+func TestHeapReadFromBranchingFactor(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []uint64{0, 1, maxSnapshotBranching + 1, math.MaxUint64} {
+		data := binary.AppendUvarint([]byte(snapshotMagic), uint64(reflect.Int))
+		data = binary.AppendUvarint(data, d)
+		data = binary.AppendUvarint(data, 0)
+		heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+		_, err := heap.ReadFrom(bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrBadSnapshot, "d=%d", d)
+		assert.Equal(t, 2, heap.BranchingFactor())
+	}
+}
+
+func TestHeapReadFromSharedStream(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	first, second := NewHeap[int](2, less), NewHeap[int](4, less)
+	first.PushAll(3, 1, 2)
+	second.PushAll(9, 8)
+
+	var buf bytes.Buffer
+	n1, err := first.WriteTo(&buf)
+	assert.NoError(t, err)
+	_, err = second.WriteTo(&buf)
+	assert.NoError(t, err)
+
+	// io.MultiReader is not an io.ByteReader, so nothing may be buffered.
+	stream := io.MultiReader(&buf)
+	restored := NewHeap[int](2, less)
+	read, err := restored.ReadFrom(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, n1, read)
+	assert.Equal(t, []int{1, 2, 3}, restored.PopN(3))
+
+	_, err = restored.ReadFrom(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, restored.BranchingFactor())
+	assert.Equal(t, []int{8, 9}, restored.PopN(2))
+}
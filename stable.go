@@ -0,0 +1,78 @@
+package heap
+
+// StableHeap is a d-ary heap that pops elements which compare equal in the
+// order they were pushed. Every push is tagged with a sequence number that
+// breaks ties in the comparator, so schedulers can run equal-priority tasks
+// first in, first out.
+type StableHeap[T any] struct {
+	entries []stableEntry[T] // Element and sequence number of each slot
+	free    []int            // Slots available for reuse
+	heap    *Heap[int]       // Occupied slots ordered by element, then sequence
+	seq     uint64           // Sequence number of the last push
+}
+
+// stableEntry is an element together with its push sequence number.
+type stableEntry[T any] struct {
+	value T
+	seq   uint64
+}
+
+// NewStableHeap creates an empty stable heap with branching factor d whose
+// elements are ordered by lessFunc, with ties broken by insertion order.
+func NewStableHeap[T any](d int, lessFunc func(T, T) bool) *StableHeap[T] {
+	s := &StableHeap[T]{}
+	s.heap = NewHeap[int](d, func(a, b int) bool {
+		ea, eb := s.entries[a], s.entries[b]
+		if lessFunc(ea.value, eb.value) {
+			return true
+		}
+		return !lessFunc(eb.value, ea.value) && ea.seq < eb.seq
+	})
+	return s
+}
+
+// Len returns the number of elements in the heap.
+func (s *StableHeap[T]) Len() int {
+	return s.heap.heapSize
+}
+
+// Push adds a new element to the heap.
+func (s *StableHeap[T]) Push(value T) {
+	s.seq++
+	e := stableEntry[T]{value: value, seq: s.seq}
+	if n := len(s.free); n > 0 {
+		slot := s.free[n-1]
+		s.free = s.free[:n-1]
+		s.entries[slot] = e
+		s.heap.Push(slot)
+		return
+	}
+	s.entries = append(s.entries, e)
+	s.heap.Push(len(s.entries) - 1)
+}
+
+// Peek returns the extremal element without removing it. Among equal
+// elements it is the one pushed first. If the heap is empty, it returns the
+// zero value of type T.
+func (s *StableHeap[T]) Peek() T {
+	if s.heap.heapSize == 0 {
+		var zero T
+		return zero
+	}
+	return s.entries[s.heap.Peek()].value
+}
+
+// Pop removes and returns the extremal element. Among equal elements it is
+// the one pushed first. If the heap is empty, it returns the zero value of
+// type T.
+func (s *StableHeap[T]) Pop() T {
+	if s.heap.heapSize == 0 {
+		var zero T
+		return zero
+	}
+	slot := s.heap.Pop()
+	value := s.entries[slot].value
+	s.entries[slot] = stableEntry[T]{}
+	s.free = append(s.free, slot)
+	return value
+}
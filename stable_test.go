@@ -0,0 +1,50 @@
+package heap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStableHeap(t *testing.T) {
+	t.Parallel()
+
+	// Tasks are "priority:name" and ordered by priority only.
+	priority := func(task string) string { return task[:strings.IndexByte(task, ':')] }
+	heap := NewStableHeap[string](3, func(a, b string) bool { return priority(a) < priority(b) })
+	assert.Zero(t, heap.Pop())
+
+	for _, task := range []string{"2:a", "1:b", "2:c", "1:d", "2:e", "1:f", "2:g"} {
+		heap.Push(task)
+	}
+	assert.Equal(t, "1:b", heap.Peek())
+	for _, want := range []string{"1:b", "1:d"} {
+		assert.Equal(t, want, heap.Pop())
+	}
+
+	// Slots freed by pops are reused without disturbing the order.
+	heap.Push("1:h")
+	heap.Push("2:i")
+	for _, want := range []string{"1:f", "1:h", "2:a", "2:c", "2:e", "2:g", "2:i"} {
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.Zero(t, heap.Len())
+}
+
+func TestStableHeapNonComparable(t *testing.T) {
+	t.Parallel()
+
+	// Batches hold slices, so they are not comparable and could not be indexed.
+	type batch struct {
+		priority int
+		ids      []int
+	}
+	heap := NewStableHeap[batch](2, func(a, b batch) bool { return a.priority < b.priority })
+	heap.Push(batch{2, []int{1, 2}})
+	heap.Push(batch{1, []int{3}})
+	heap.Push(batch{2, []int{4}})
+	assert.Equal(t, []int{3}, heap.Pop().ids)
+	assert.Equal(t, []int{1, 2}, heap.Pop().ids)
+	assert.Equal(t, []int{4}, heap.Pop().ids)
+}
@@ -0,0 +1,58 @@
+package heap
+
+// WithStagingBuffer is an option that absorbs pushes into an unsorted staging
+// area of up to size elements. A staged push only appends the element in O(1);
+// the staged elements are merged into the heap when the area fills up or when
+// an operation that depends on the order, such as Peek or Pop, next runs. This
+// smooths latency for producers that push in bursts much faster than consumers
+// drain, while bounding the work deferred to any single merge.
+func WithStagingBuffer[T comparable](size int) Option[T] {
+	return func(h *Heap[T]) {
+		h.stageSize = size
+	}
+}
+
+// Staged returns the number of pushed elements waiting in the staging buffer.
+func (h *Heap[T]) Staged() int {
+	return h.staged
+}
+
+// stage records that the element just appended at the end of the heap is
+// staged, merging the staging buffer if it is full.
+func (h *Heap[T]) stage() {
+	h.staged++
+	if h.staged >= h.stageSize {
+		h.mergeStaged()
+	}
+}
+
+// mergeStaged restores the heap property over the staged elements at the end
+// of the heap. Small batches are sifted up one at a time; a batch that makes
+// up most of the heap is merged with a single O(n) heapify instead.
+func (h *Heap[T]) mergeStaged() {
+	staged := h.staged
+	if staged == 0 {
+		return
+	}
+	h.staged = 0
+	if staged > h.heapSize/2 {
+		h.heapify()
+		return
+	}
+	for i := h.heapSize - staged; i < h.heapSize; i++ {
+		levels := h.up(i)
+		if h.profile != nil {
+			h.profile.record(SiftUp, levels)
+		}
+	}
+}
+
+// unstage folds any staged elements into the dirty flag, so the whole heap is
+// re-heapified on its next read. Operations that replace the storage wholesale
+// use it to avoid carrying staged positions across.
+func (h *Heap[T]) unstage() {
+	if h.staged > 0 {
+		h.staged = 0
+		h.dirty = true
+	}
+}
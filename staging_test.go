@@ -0,0 +1,83 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStagingBuffer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		size   int
+		pushes int
+	}{
+		{name: "merged on pop", size: 64, pushes: 10},
+		{name: "merged when full", size: 4, pushes: 100},
+		{name: "batch larger than heap", size: 1000, pushes: 200},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewSource(1))
+			heap := NewHeap[int](3, func(a, b int) bool { return a < b }, WithStagingBuffer[int](tt.size))
+			values := make([]int, tt.pushes)
+			for i := range values {
+				values[i] = rng.Intn(50)
+				heap.Push(values[i])
+				assert.Less(t, heap.Staged(), tt.size)
+			}
+			assert.Equal(t, tt.pushes%tt.size, heap.Staged())
+			assert.True(t, heap.Contains(values[len(values)-1]), "staged element not indexed")
+
+			sort.Ints(values)
+			assert.Equal(t, values[0], heap.Peek())
+			assert.Zero(t, heap.Staged())
+			for _, want := range values {
+				assert.Equal(t, want, heap.Pop())
+			}
+			assert.Empty(t, heap.index)
+		})
+	}
+}
+
+func TestStagingBufferInterleaved(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(2))
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithStagingBuffer[int](8))
+	reference := NewHeap[int](4, func(a, b int) bool { return a < b })
+	for i := 0; i < 1000; i++ {
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, reference.Pop(), heap.Pop())
+			continue
+		}
+		v := rng.Intn(100)
+		heap.Push(v)
+		reference.Push(v)
+	}
+}
+
+func TestStagingBufferSwap(t *testing.T) {
+	t.Parallel()
+
+	staged := NewHeap[int](2, func(a, b int) bool { return a < b }, WithStagingBuffer[int](16))
+	plain := NewHeap[int](2, func(a, b int) bool { return a < b })
+	for _, v := range []int{5, 3, 9, 1} {
+		staged.Push(v)
+	}
+	staged.Swap(plain)
+	plain.Push(0)
+
+	assert.Zero(t, staged.Staged())
+	for _, want := range []int{0, 1, 3, 5, 9} {
+		assert.Equal(t, want, plain.Pop())
+	}
+}
@@ -0,0 +1,60 @@
+package heap
+
+// Stats holds operation counters collected by a heap created with WithStats,
+// and wait-time statistics collected by one created with WithWaitTracking.
+type Stats struct {
+	Pushes    uint64    // Elements added, by any method
+	Pops      uint64    // Extremal elements removed by Pop, PushPop and Replace
+	Swaps     uint64    // Element swaps performed while sifting and removing
+	MaxDepth  int       // Depth of the deepest level the tree has reached, zero for a lone root
+	IndexSize int       // Distinct keys currently held by the index
+	Waits     WaitStats // Wait times and percentiles of popped elements, as reported by WaitStats
+}
+
+// statsCollector accumulates Stats.
+type statsCollector struct {
+	stats   Stats
+	maxSize int
+}
+
+// WithStats is an option that counts pushes, pops and swaps and tracks how
+// deep the tree grows, so the branching factor can be tuned against a
+// production workload: a larger d trades fewer levels for more comparisons
+// per level. See Stats.
+func WithStats[T comparable]() Option[T] {
+	return func(h *Heap[T]) {
+		h.stats = &statsCollector{}
+	}
+}
+
+// Stats returns the counters collected since the heap was created. The
+// counters are zero unless the heap was created with WithStats, and Waits is
+// zero unless it was created with WithWaitTracking.
+func (h *Heap[T]) Stats() Stats {
+	var s Stats
+	if h.stats != nil {
+		s = h.stats.stats
+		for n := h.stats.maxSize - 1; n > 0; n = h.parent(n) {
+			s.MaxDepth++
+		}
+		switch {
+		case h.noIndex:
+		case h.keyed != nil:
+			s.IndexSize = h.keyed.size()
+		case h.hash != nil:
+			s.IndexSize = len(h.hashed)
+		default:
+			s.IndexSize = len(h.index)
+		}
+	}
+	if h.wait != nil {
+		s.Waits = h.wait.waits.stats()
+	}
+	return s
+}
+
+// pushed counts an element added to h.
+func (s *statsCollector) pushed(size int) {
+	s.stats.Pushes++
+	s.maxSize = max(s.maxSize, size)
+}
@@ -0,0 +1,35 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapStats(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b }, WithStats[int]())
+	assert.Equal(t, Stats{}, heap.Stats())
+
+	for _, v := range []int{3, 2, 1} {
+		heap.Push(v) // Each push after the first moves the new element to the root
+	}
+	assert.Equal(t, Stats{Pushes: 3, Swaps: 2, MaxDepth: 1, IndexSize: 3}, heap.Stats())
+
+	assert.Equal(t, 1, heap.Pop())      // Swaps the last element to the root, which stays
+	assert.Equal(t, 0, heap.PushPop(0)) // Returns immediately without touching the heap
+	assert.Equal(t, 2, heap.Replace(5)) // Pops and pushes, sifting 5 below 3
+	assert.Equal(t, Stats{Pushes: 4, Pops: 2, Swaps: 4, MaxDepth: 1, IndexSize: 2}, heap.Stats())
+
+	heap.PushAll(7, 7)
+	stats := heap.Stats()
+	assert.Equal(t, uint64(6), stats.Pushes)
+	assert.Equal(t, 2, stats.MaxDepth)
+	assert.Equal(t, 3, stats.IndexSize, "equal elements share an index key")
+
+	clone := heap.Clone()
+	clone.Pop()
+	assert.Equal(t, uint64(2), heap.Stats().Pops, "clone shares counters")
+	assert.Equal(t, Stats{}, NewHeap[int](2, func(a, b int) bool { return a < b }).Stats())
+}
@@ -0,0 +1,85 @@
+package heap
+
+import "cmp"
+
+// EventKind identifies the type of a sweep-line event. When several events
+// share a position they are processed in the order End, Start, Point, which
+// treats intervals as half-open like Interval: a point at an interval's start
+// is inside it and a point at its end is not.
+type EventKind int
+
+const (
+	// EventEnd marks the end of an interval.
+	EventEnd EventKind = iota
+	// EventStart marks the start of an interval.
+	EventStart
+	// EventPoint marks a single position.
+	EventPoint
+)
+
+// SweepEvent is an event on a sweep line at position At, carrying Value.
+type SweepEvent[T cmp.Ordered, V any] struct {
+	At    T
+	Kind  EventKind
+	Value V
+}
+
+// SweepLine is a position-ordered event queue for sweep-line algorithms such
+// as overlap counting or skyline computation. Events are delivered to a
+// handler in position order; events at the same position are ordered by kind
+// and then by the order they were added.
+type SweepLine[T cmp.Ordered, V any] struct {
+	events []SweepEvent[T, V] // Events added since the queue was last empty
+	queue  *Heap[int]         // Pending events, as indices into events
+}
+
+// NewSweepLine creates an empty sweep line.
+func NewSweepLine[T cmp.Ordered, V any]() *SweepLine[T, V] {
+	s := &SweepLine[T, V]{}
+	s.queue = NewHeap[int](4, s.less)
+	return s
+}
+
+// less orders events by position, then kind, then insertion order.
+func (s *SweepLine[T, V]) less(i, j int) bool {
+	a, b := s.events[i], s.events[j]
+	if a.At != b.At {
+		return a.At < b.At
+	}
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	return i < j
+}
+
+// Add queues an event.
+func (s *SweepLine[T, V]) Add(e SweepEvent[T, V]) {
+	s.events = append(s.events, e)
+	s.queue.Push(len(s.events) - 1)
+}
+
+// AddInterval queues a start event at start and an end event at end.
+func (s *SweepLine[T, V]) AddInterval(start, end T, value V) {
+	s.Add(SweepEvent[T, V]{At: start, Kind: EventStart, Value: value})
+	s.Add(SweepEvent[T, V]{At: end, Kind: EventEnd, Value: value})
+}
+
+// AddPoint queues a point event at at.
+func (s *SweepLine[T, V]) AddPoint(at T, value V) {
+	s.Add(SweepEvent[T, V]{At: at, Kind: EventPoint, Value: value})
+}
+
+// Len returns the number of pending events.
+func (s *SweepLine[T, V]) Len() int {
+	return s.queue.heapSize
+}
+
+// Run delivers every pending event to handler in order. The handler may add
+// further events, such as intersections discovered during the sweep; events
+// added behind the current position are delivered next.
+func (s *SweepLine[T, V]) Run(handler func(SweepEvent[T, V])) {
+	for s.queue.heapSize > 0 {
+		handler(s.events[s.queue.Pop()])
+	}
+	s.events = s.events[:0]
+}
@@ -0,0 +1,61 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSweepLineOrder(t *testing.T) {
+	s := NewSweepLine[int, string]()
+	s.AddPoint(5, "p5")
+	s.AddInterval(0, 5, "a")
+	s.AddInterval(5, 8, "b")
+	s.AddPoint(0, "p0")
+	assert.Equal(t, 6, s.Len())
+
+	var got []string
+	s.Run(func(e SweepEvent[int, string]) {
+		got = append(got, e.Value)
+	})
+	assert.Equal(t, []string{"a", "p0", "a", "b", "p5", "b"}, got)
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestSweepLineOverlapAtPoints(t *testing.T) {
+	s := NewSweepLine[int, int]()
+	s.AddInterval(0, 10, 0)
+	s.AddInterval(2, 6, 0)
+	s.AddInterval(4, 12, 0)
+	for _, p := range []int{1, 4, 6, 11} {
+		s.AddPoint(p, p)
+	}
+
+	active := 0
+	coverage := make(map[int]int)
+	s.Run(func(e SweepEvent[int, int]) {
+		switch e.Kind {
+		case EventStart:
+			active++
+		case EventEnd:
+			active--
+		case EventPoint:
+			coverage[e.Value] = active
+		}
+	})
+	assert.Equal(t, map[int]int{1: 1, 4: 3, 6: 2, 11: 1}, coverage)
+}
+
+func TestSweepLineHandlerAddsEvents(t *testing.T) {
+	s := NewSweepLine[int, int]()
+	s.AddPoint(1, 1)
+
+	var got []int
+	s.Run(func(e SweepEvent[int, int]) {
+		got = append(got, e.At)
+		if e.At < 4 {
+			s.AddPoint(e.At+1, 0)
+		}
+	})
+	assert.Equal(t, []int{1, 2, 3, 4}, got)
+}
@@ -0,0 +1,81 @@
+package heap
+
+// LazyRemove marks one occurrence of element as deleted without restructuring
+// the heap, which makes cancellation O(1) on average. Deleted elements are
+// skipped when they reach the root, discarded by Pop and Peek in O(log n)
+// each, and excluded from Len. Once they outnumber the live elements, or
+// before any operation other than Push, Pop and Peek inspects the heap, they
+// are purged in O(n). It returns false if element is not in the heap or every
+// occurrence of it is already deleted.
+func (h *Heap[T]) LazyRemove(element T) bool {
+	if h.tombs[element] >= h.count(element) {
+		return false
+	}
+	if h.tombs == nil {
+		h.tombs = make(map[T]int)
+	}
+	h.tombs[element]++
+	h.dead++
+	if h.dead > h.heapSize-h.dead {
+		h.purge()
+	}
+	return true
+}
+
+// count returns the number of occurrences of element, deleted or not.
+func (h *Heap[T]) count(element T) int {
+	if !h.noIndex && h.keyed == nil && h.hash == nil {
+		return len(h.index[element])
+	}
+	n := 0
+	for _, v := range h.data[:h.heapSize] {
+		if v == element {
+			n++
+		}
+	}
+	return n
+}
+
+// ensureTop restores the heap property and discards deleted and expired
+// elements from the root until it holds a live element, for operations that
+// only read the root.
+func (h *Heap[T]) ensureTop() {
+	h.ensureOrder()
+	for h.heapSize > 0 {
+		switch {
+		case h.dead > 0 && h.tombs[h.data[0]] > 0:
+			h.bury(0)
+		case h.ttl != nil && h.ttl.expired(0, h.ttl.clock.Now()):
+			h.removeAt(0)
+		default:
+			return
+		}
+	}
+}
+
+// purge removes every deleted element in O(n + k log n) for k deleted elements.
+func (h *Heap[T]) purge() {
+	if h.dead == 0 {
+		return
+	}
+	h.ensureOrder()
+	// Scanning backwards, removeAt only moves an unscanned element into the
+	// position just removed from, so rechecking that position finds it.
+	for i := h.heapSize - 1; i >= 0 && h.dead > 0; {
+		if i < h.heapSize && h.tombs[h.data[i]] > 0 {
+			h.bury(i)
+			continue
+		}
+		i--
+	}
+}
+
+// bury removes the deleted element at index i and its tombstone.
+func (h *Heap[T]) bury(i int) {
+	v := h.data[i]
+	if h.tombs[v]--; h.tombs[v] == 0 {
+		delete(h.tombs, v)
+	}
+	h.dead--
+	h.removeAt(i)
+}
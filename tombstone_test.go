@@ -0,0 +1,98 @@
+package heap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapLazyRemove(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b })
+	for _, v := range []int{5, 1, 7, 3, 3, 9, 2, 8} {
+		heap.Push(v)
+	}
+
+	assert.True(t, heap.LazyRemove(1))
+	assert.False(t, heap.LazyRemove(1), "only one occurrence of 1")
+	assert.False(t, heap.LazyRemove(4))
+	assert.True(t, heap.LazyRemove(3))
+	assert.True(t, heap.LazyRemove(8))
+	assert.Equal(t, 5, heap.Len())
+	assert.Equal(t, 8, heap.heapSize, "deletion is deferred")
+
+	// The deleted root is skipped.
+	assert.Equal(t, 2, heap.Peek())
+	assert.Equal(t, 2, heap.Pop())
+	assert.Equal(t, 3, heap.Pop(), "one occurrence of 3 is still live")
+
+	// Lookups purge the remaining tombstones first.
+	assert.False(t, heap.Contains(8))
+	assert.Zero(t, heap.dead)
+	assert.Empty(t, heap.tombs)
+	assert.Equal(t, heap.Len(), heap.heapSize)
+
+	for _, want := range []int{5, 7, 9} {
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.True(t, heap.IsEmpty())
+	assert.Empty(t, heap.index)
+}
+
+func TestHeapLazyRemovePurgesWhenMostlyDead(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	for v := 0; v < 100; v++ {
+		heap.Push(v)
+	}
+	for v := 99; v >= 50; v-- {
+		assert.True(t, heap.LazyRemove(v))
+	}
+	assert.Equal(t, 50, heap.dead)
+
+	// One more tombstone outnumbers the live elements and triggers a purge.
+	assert.True(t, heap.LazyRemove(0))
+	assert.Zero(t, heap.dead)
+	assert.Equal(t, 49, heap.heapSize)
+	for want := 1; want < 50; want++ {
+		assert.Equal(t, want, heap.Pop())
+	}
+}
+
+func TestHeapLazyRemoveRandom(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(1))
+	for _, d := range []int{2, 3, 4} {
+		heap := NewHeap[int](d, func(a, b int) bool { return a < b })
+		live := map[int]int{}
+		for i := 0; i < 500; i++ {
+			v := rng.Intn(200)
+			heap.Push(v)
+			live[v]++
+		}
+		for i := 0; i < 200; i++ {
+			v := rng.Intn(200)
+			assert.Equal(t, live[v] > 0, heap.LazyRemove(v))
+			if live[v] > 0 {
+				live[v]--
+			}
+		}
+
+		got := map[int]int{}
+		prev := -1
+		for !heap.IsEmpty() {
+			v := heap.Pop()
+			assert.LessOrEqual(t, prev, v, "d=%d", d)
+			got[v]++
+			prev = v
+		}
+		for v, n := range live {
+			assert.Equal(t, n, got[v], "d=%d v=%d", d, v)
+		}
+		assert.Empty(t, heap.index)
+	}
+}
@@ -0,0 +1,42 @@
+package heap
+
+import (
+	"fmt"
+	"slices"
+)
+
+// TopK keeps the k elements that order first under a less function out of a
+// stream of elements, in O(log k) time per element and O(k) space. Pass
+// a < b to keep the k smallest elements and a > b to keep the k largest.
+type TopK[T comparable] struct {
+	heap *Heap[T] // Bounded heap with the worst kept element at the root
+}
+
+// NewTopK creates a TopK keeping the k elements that order first under less.
+// It panics if k is less than 1.
+func NewTopK[T comparable](k int, less func(T, T) bool) *TopK[T] {
+	if k < 1 {
+		panic(fmt.Sprintf("heap: top-k size %d is not positive", k))
+	}
+	worstFirst := func(a, b T) bool { return less(b, a) }
+	return &TopK[T]{heap: NewHeap(4, worstFirst, WithCapacity[T](k), WithMaxSize[T](k))}
+}
+
+// Add offers v to the TopK. It is kept if fewer than k elements have been
+// seen or if it orders before the worst element kept so far.
+func (t *TopK[T]) Add(v T) {
+	t.heap.Push(v)
+}
+
+// Len returns the number of elements kept, which is at most k.
+func (t *TopK[T]) Len() int {
+	return t.heap.Len()
+}
+
+// Result returns the kept elements in order, best first. The TopK is not
+// modified and may keep accepting elements.
+func (t *TopK[T]) Result() []T {
+	result := t.heap.ToSortedSlice()
+	slices.Reverse(result)
+	return result
+}
@@ -0,0 +1,56 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopK(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(1))
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = rng.Intn(10000)
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	tests := []struct {
+		name string
+		k    int
+		less func(a, b int) bool
+		want []int
+	}{
+		{name: "smallest", k: 5, less: func(a, b int) bool { return a < b }, want: sorted[:5]},
+		{name: "largest", k: 3, less: func(a, b int) bool { return a > b }, want: []int{sorted[999], sorted[998], sorted[997]}},
+		{name: "k exceeds stream", k: 2000, less: func(a, b int) bool { return a < b }, want: sorted},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			top := NewTopK(tt.k, tt.less)
+			assert.Empty(t, top.Result())
+			for _, v := range values {
+				top.Add(v)
+			}
+			assert.Equal(t, len(tt.want), top.Len())
+			assert.Equal(t, tt.want, top.Result())
+			assert.Equal(t, tt.want, top.Result(), "Result modified the TopK")
+		})
+	}
+}
+
+func TestTopKInvalidSize(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	assert.PanicsWithValue(t, "heap: top-k size 0 is not positive", func() { NewTopK(0, less) })
+	assert.PanicsWithValue(t, "heap: top-k size -1 is not positive", func() { NewTopK(-1, less) })
+}
@@ -0,0 +1,93 @@
+package heap
+
+import "time"
+
+// ttlTracker records when each element expires.
+type ttlTracker struct {
+	clock   Clock
+	expires []time.Time // Expiry of each element, parallel to Heap.data, zero for never
+	pending time.Time   // Expiry for the element being pushed by PushWithTTL
+}
+
+// WithTTLClock is an option that reads the current time from clock when
+// deciding whether elements pushed with PushWithTTL have expired. Without it
+// the system clock is used.
+func WithTTLClock[T comparable](clock Clock) Option[T] {
+	return func(h *Heap[T]) {
+		h.expiryTracker().clock = clock
+	}
+}
+
+// PushWithTTL pushes value so that it expires ttl from now. Pop, Peek and the
+// other operations that read the root discard expired elements instead of
+// returning them, and the ordered readers such as PeekN, Kth and Ordered skip
+// them. Expired elements below the root remain visible to Len, Contains, All
+// and Values until they reach it or PurgeExpired removes them. Elements added
+// by other methods never expire.
+func (h *Heap[T]) PushWithTTL(value T, ttl time.Duration) {
+	t := h.expiryTracker()
+	t.pending = t.clock.Now().Add(ttl)
+	h.Push(value)
+	t.pending = time.Time{}
+}
+
+// PurgeExpired removes every expired element in one O(n) pass and returns the
+// number removed.
+func (h *Heap[T]) PurgeExpired() int {
+	if h.ttl == nil {
+		return 0
+	}
+	now := h.ttl.clock.Now()
+	return h.removeWhere(func(i int) bool { return h.ttl.expired(i, now) })
+}
+
+// live returns the elements that have not expired, in array order. When no
+// element can have expired it is the heap's own storage, so callers must not
+// modify it.
+func (h *Heap[T]) live() []T {
+	h.ensureHeap()
+	if h.ttl == nil {
+		return h.data[:h.heapSize]
+	}
+	now := h.ttl.clock.Now()
+	out := make([]T, 0, h.heapSize)
+	for i, v := range h.data[:h.heapSize] {
+		if !h.ttl.expired(i, now) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// expiryTracker returns the heap's expiry tracker, creating it on first use
+// with no element expiring.
+func (h *Heap[T]) expiryTracker() *ttlTracker {
+	if h.ttl == nil {
+		h.ttl = &ttlTracker{clock: SystemClock{}, expires: make([]time.Time, h.heapSize, cap(h.data))}
+	}
+	return h.ttl
+}
+
+// inheritTTL makes h track expiry on src's clock when src tracks it and h
+// does not, so elements moved from src keep their expiry.
+func (h *Heap[T]) inheritTTL(src *Heap[T]) {
+	if src.ttl != nil && h.ttl == nil {
+		h.ttl = &ttlTracker{clock: src.ttl.clock, expires: make([]time.Time, h.heapSize, cap(h.data))}
+	}
+}
+
+// stamp records the expiry of the element just stored at index i, which is
+// the pending expiry during PushWithTTL and never otherwise.
+func (t *ttlTracker) stamp(i int) {
+	if i == len(t.expires) {
+		t.expires = append(t.expires, t.pending)
+		return
+	}
+	t.expires[i] = t.pending
+}
+
+// expired reports whether the element at index i has expired at now.
+func (t *ttlTracker) expired(i int, now time.Time) bool {
+	e := t.expires[i]
+	return !e.IsZero() && !now.Before(e)
+}
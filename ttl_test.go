@@ -0,0 +1,212 @@
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushWithTTL(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	h := NewMin[int](3, WithTTLClock[int](clock))
+
+	h.PushWithTTL(1, time.Second)
+	h.PushWithTTL(2, time.Minute)
+	h.Push(3)
+	h.PushWithTTL(4, time.Second)
+	assert.Equal(t, 1, h.Peek())
+
+	clock.RunUntil(start.Add(time.Second))
+	assert.Equal(t, 2, h.Peek(), "1 expired")
+	assert.Equal(t, 3, h.Len(), "4 is expired but below the root")
+	assert.Equal(t, []int{2, 3}, h.PopN(3), "4 expired before reaching the root")
+	assert.True(t, h.IsEmpty())
+	assert.NoError(t, h.Verify())
+}
+
+func TestPushWithTTLSystemClock(t *testing.T) {
+	t.Parallel()
+
+	h := NewMin[int](2)
+	h.Push(5)
+	h.PushWithTTL(1, -time.Second)
+	h.PushWithTTL(2, time.Hour)
+	assert.Equal(t, 2, h.Pop())
+	assert.Equal(t, 5, h.Pop())
+	assert.True(t, h.IsEmpty())
+}
+
+func TestPurgeExpired(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	h := NewMin[int](4, WithTTLClock[int](clock))
+	assert.Zero(t, NewMin[int](4).PurgeExpired())
+
+	for i := 0; i < 100; i++ {
+		if i%3 == 0 {
+			h.Push(i)
+		} else {
+			h.PushWithTTL(i, time.Duration(i)*time.Second)
+		}
+	}
+	clock.RunUntil(start.Add(50 * time.Second))
+
+	// Elements 1..50 not divisible by 3 have expired.
+	assert.Equal(t, 34, h.PurgeExpired())
+	assert.Equal(t, 66, h.Len())
+	assert.False(t, h.Contains(49))
+	assert.True(t, h.Contains(51))
+	assert.NoError(t, h.Verify())
+	assert.Equal(t, 0, h.Pop())
+	assert.Equal(t, 3, h.Pop())
+}
+
+func TestPushWithTTLRandom(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	h := NewMin[int](3, WithTTLClock[int](clock))
+	expires := map[int]time.Time{}
+	rng := rand.New(rand.NewSource(11))
+
+	for i := 0; i < 2000; i++ {
+		clock.RunUntil(clock.Now().Add(time.Duration(rng.Intn(3)) * time.Second))
+		switch rng.Intn(4) {
+		case 0, 1:
+			v := rng.Intn(1 << 20)
+			if _, ok := expires[v]; ok || h.Contains(v) {
+				continue
+			}
+			ttl := time.Duration(rng.Intn(20)) * time.Second
+			h.PushWithTTL(v, ttl)
+			expires[v] = clock.Now().Add(ttl)
+		case 2:
+			if h.IsEmpty() {
+				continue
+			}
+			v := h.Pop()
+			assert.True(t, clock.Now().Before(expires[v]), "popped expired %d", v)
+			delete(expires, v)
+		case 3:
+			h.PurgeExpired()
+		}
+		assert.NoError(t, h.Verify())
+	}
+}
+
+func TestPushWithTTLCloneSwap(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	h := NewMin[int](2, WithTTLClock[int](clock))
+	h.PushWithTTL(1, time.Second)
+	h.Push(2)
+
+	c := h.Clone()
+	melded := NewMin[int](2)
+	melded.Push(3)
+	melded.Meld(c.Clone())
+	other := NewMin[int](2)
+	other.PushAll(0, 7)
+	h.Swap(other)
+
+	clock.RunUntil(start.Add(time.Second))
+	assert.Equal(t, 2, c.Peek(), "clone keeps expiry")
+	assert.Equal(t, 2, other.Peek(), "expiry moves with the elements")
+	assert.Equal(t, 0, h.Peek(), "swapped-in elements never expire")
+	assert.Equal(t, []int{2, 3}, melded.PopN(2), "melded elements keep expiry")
+	assert.NoError(t, h.Verify())
+	assert.NoError(t, other.Verify())
+}
+
+func TestPushWithTTLReaders(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	newHeap := func() (*Heap[int], *EventClock) {
+		clock := NewVirtualClock(start)
+		h := NewMin[int](2, WithTTLClock[int](clock))
+		h.PushWithTTL(1, time.Second)
+		h.PushWithTTL(2, time.Second)
+		h.PushWithTTL(3, time.Minute)
+		return h, clock
+	}
+
+	allExpired := map[string]func(h *Heap[int]) bool{
+		"PeekOptional": func(h *Heap[int]) bool { return h.PeekOptional().IsNone() },
+		"PopOptional":  func(h *Heap[int]) bool { return h.PopOptional().IsNone() },
+		"PeekN":        func(h *Heap[int]) bool { return len(h.PeekN(1)) == 0 },
+		"Drain":        func(h *Heap[int]) bool { return len(slices.Collect(h.Drain())) == 0 },
+	}
+	for name, empty := range allExpired {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			h, clock := newHeap()
+			clock.RunUntil(start.Add(time.Hour))
+			assert.True(t, empty(h), "every element expired")
+		})
+	}
+
+	t.Run("expired below the root", func(t *testing.T) {
+		t.Parallel()
+
+		h, clock := newHeap()
+		h.Push(0)
+		h.PushWithTTL(4, time.Minute)
+		clock.RunUntil(start.Add(time.Second))
+		assert.Equal(t, []int{0, 3, 4}, h.PeekN(5), "1 and 2 skipped")
+		assert.Equal(t, Some(0), h.PeekOptional())
+		assert.Equal(t, Some(0), h.PopOptional())
+		assert.Equal(t, []int{3, 4}, slices.Collect(h.Drain()))
+	})
+}
+
+func TestPushWithTTLOrderedReaders(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	h := NewMin[int](2, WithTTLClock[int](clock))
+	h.PushAll(0, 6)
+	h.PushWithTTL(1, time.Second)
+	h.PushWithTTL(2, time.Second)
+	h.PushWithTTL(3, time.Minute)
+	h.Push(4)
+	assert.True(t, h.LazyRemove(6))
+	clock.RunUntil(start.Add(time.Second))
+
+	want := []int{0, 3, 4}
+	for k, v := range want {
+		got, ok := h.Kth(k + 1)
+		assert.True(t, ok)
+		assert.Equal(t, v, got, "Kth(%d)", k+1)
+	}
+	_, ok := h.KthSmallest(len(want) + 1)
+	assert.False(t, ok, "expired elements counted")
+	second, ok := h.Second()
+	assert.True(t, ok)
+	assert.Equal(t, 3, second)
+	assert.Equal(t, want, h.ToSortedSlice())
+	assert.Equal(t, want, slices.Collect(h.Snapshot().All()))
+	assert.Equal(t, want, slices.Collect(h.Ordered()))
+
+	// Once the root expires too, Kth(1) agrees with Peek.
+	h.Pop()
+	clock.RunUntil(start.Add(time.Minute))
+	first, ok := h.Kth(1)
+	assert.True(t, ok)
+	assert.Equal(t, h.Peek(), first)
+	_, ok = h.Second()
+	assert.False(t, ok)
+}
@@ -0,0 +1,147 @@
+package heap
+
+import "math/bits"
+
+// Change describes replacing the element Old with New.
+type Change[T comparable] struct {
+	Old T
+	New T
+}
+
+// Update replaces one occurrence of old with value and re-sifts it in place in
+// O(d log_d n), which makes decrease-key style algorithms such as Dijkstra's
+// possible without rebuilding the heap. It returns false if old is not in the
+// heap.
+func (h *Heap[T]) Update(old, value T) bool {
+	h.mergeStaged()
+	i, exists := h.find(old)
+	if !exists {
+		return false
+	}
+	h.updateAt(i, value)
+	return true
+}
+
+// UpdateFunc replaces one occurrence of element with f applied to it and
+// re-sifts it in place, returning false if element is not in the heap. f
+// receives the stored element, which under WithIdentity may differ from the
+// element passed in.
+func (h *Heap[T]) UpdateFunc(element T, f func(T) T) bool {
+	h.mergeStaged()
+	i, exists := h.find(element)
+	if !exists {
+		return false
+	}
+	h.updateAt(i, f(h.data[i]))
+	return true
+}
+
+// updateAt replaces the element at index i with value and re-sifts it. Staged
+// elements must already have been merged.
+func (h *Heap[T]) updateAt(i int, value T) {
+	old := h.data[i]
+	h.replaceAt(i, value)
+	if !h.dirty {
+		h.fix(i)
+	}
+	if h.agg != nil {
+		h.agg.added(h, value)
+		h.agg.removed(h, old)
+	}
+	if h.oplog != nil {
+		h.log(Op[T]{Kind: OpUpdate, Value: value, Old: old})
+	}
+}
+
+// UpdateBatch applies many changes at once and returns the number applied.
+// Each change replaces one occurrence of Old with New; changes whose Old is
+// not in the heap are skipped. A small batch is applied with one sift per
+// change, while a batch large enough that the sifts would cost more than
+// rebuilding is applied in place and followed by a single O(n) heapify.
+// UpdateHandles does the same for elements pushed with PushHandle.
+func (h *Heap[T]) UpdateBatch(changes []Change[T]) int {
+	return h.updateBatch(len(changes), func(k int) (int, T, bool) {
+		i, ok := h.find(changes[k].Old)
+		return i, changes[k].New, ok
+	})
+}
+
+// updateBatch applies n changes, locating the k-th with locate, which returns
+// the index of the element to replace, its new value and false if the change
+// is to be skipped. See UpdateBatch.
+func (h *Heap[T]) updateBatch(n int, locate func(k int) (int, T, bool)) int {
+	h.mergeStaged()
+	rebuild := n*bits.Len(uint(h.heapSize)) >= h.heapSize
+	applied := 0
+	for k := 0; k < n; k++ {
+		i, value, ok := locate(k)
+		if !ok {
+			continue
+		}
+		old := h.data[i]
+		h.replaceAt(i, value)
+		if !rebuild && !h.dirty {
+			h.fix(i)
+		}
+		if h.oplog != nil {
+			h.log(Op[T]{Kind: OpUpdate, Value: value, Old: old})
+		}
+		applied++
+	}
+	if rebuild && applied > 0 && !h.dirty {
+		h.heapify()
+	}
+	if h.agg != nil && applied > 0 {
+		h.agg.recompute(h)
+	}
+	return applied
+}
+
+// replaceAt stores value at index i in place of the current element, keeping
+// the index up to date. The caller must restore the heap property.
+func (h *Heap[T]) replaceAt(i int, value T) {
+	old := h.data[i]
+	if old == value {
+		return
+	}
+	h.removeIndex(old, i)
+	h.data[i] = value
+	h.addIndex(value, i)
+}
+
+// AdjustAllFunc replaces every element v with f(v). When f preserves the heap
+// order, as shifting every priority by the same amount does in epoch-based
+// aging, the elements are rewritten in place with no sifting; otherwise the
+// heap is rebuilt in O(n). Either way the index is rebuilt, so the call costs
+// O(n).
+func (h *Heap[T]) AdjustAllFunc(f func(T) T) {
+	h.ensureHeap()
+	h.clearIndex()
+	ordered := true
+	for i := 0; i < h.heapSize; i++ {
+		h.data[i] = f(h.data[i])
+		h.addIndex(h.data[i], i)
+		if i > 0 && ordered && h.lessFunc(h.data[i], h.data[h.parent(i)]) {
+			ordered = false
+		}
+	}
+	if !ordered {
+		h.heapify()
+	}
+	if h.agg != nil {
+		h.agg.recompute(h)
+	}
+	h.logContents()
+}
+
+// number is the set of types AdjustAll can shift by a delta.
+type number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// AdjustAll adds delta to every element of a numeric heap. See AdjustAllFunc.
+func AdjustAll[T number](h *Heap[T], delta T) {
+	h.AdjustAllFunc(func(v T) T { return v + delta })
+}
@@ -0,0 +1,173 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateBatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		n       int
+		changes int
+	}{
+		{name: "small batch sifts", n: 1000, changes: 5},
+		{name: "large batch rebuilds", n: 100, changes: 80},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewSource(int64(tt.n)))
+			heap := NewHeap[int](4, func(a, b int) bool { return a < b }, WithAggregates(func(v int) float64 { return float64(v) }))
+			values := make([]int, tt.n)
+			for i := range values {
+				values[i] = i * 10
+				heap.Push(values[i])
+			}
+
+			changes := make([]Change[int], 0, tt.changes+1)
+			for _, k := range rng.Perm(tt.n)[:tt.changes] {
+				next := rng.Intn(tt.n*10) + 1
+				changes = append(changes, Change[int]{Old: values[k], New: next})
+				values[k] = next
+			}
+			changes = append(changes, Change[int]{Old: -1, New: 0}) // Not in the heap
+
+			assert.Equal(t, tt.changes, heap.UpdateBatch(changes))
+
+			sort.Ints(values)
+			sum := 0
+			for _, v := range values {
+				sum += v
+			}
+			assert.Equal(t, float64(sum), heap.Aggregates().Sum)
+			for _, want := range values {
+				assert.Equal(t, want, heap.Pop())
+			}
+			assert.Empty(t, heap.index)
+		})
+	}
+}
+
+func TestUpdateBatchOpLog(t *testing.T) {
+	t.Parallel()
+
+	mirror := NewHeap[int](2, func(a, b int) bool { return a < b })
+	primary := NewHeap[int](2, func(a, b int) bool { return a < b }, WithOpLog(mirror.Apply))
+	for _, v := range []int{5, 3, 8} {
+		primary.Push(v)
+	}
+	primary.UpdateBatch([]Change[int]{{Old: 8, New: 1}, {Old: 3, New: 9}})
+
+	for _, want := range []int{1, 5, 9} {
+		assert.Equal(t, want, mirror.Pop())
+	}
+}
+
+func TestAdjustAll(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](3, func(a, b int) bool { return a < b }, WithAggregates(func(v int) float64 { return float64(v) }))
+	for _, v := range []int{4, 1, 7, 3, 9} {
+		heap.Push(v)
+	}
+
+	AdjustAll(heap, 10)
+	assert.True(t, heap.Contains(17))
+	assert.False(t, heap.Contains(7))
+	assert.Equal(t, float64(74), heap.Aggregates().Sum)
+
+	// Negating reverses the order, so the heap is rebuilt.
+	heap.AdjustAllFunc(func(v int) int { return -v })
+	for _, want := range []int{-19, -17, -14, -13, -11} {
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.Empty(t, heap.index)
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b }, WithAggregates(func(v int) float64 { return float64(v) }))
+	for _, v := range []int{10, 20, 30, 40, 50} {
+		heap.Push(v)
+	}
+
+	assert.True(t, heap.Update(40, 5), "decrease-key")
+	assert.Equal(t, 5, heap.Peek())
+	assert.True(t, heap.Update(5, 45), "increase-key")
+	assert.True(t, heap.Update(50, 1))
+	assert.False(t, heap.Update(99, 0))
+	assert.False(t, heap.Contains(40))
+
+	aggregates := heap.Aggregates()
+	assert.Equal(t, float64(106), aggregates.Sum)
+	assert.Equal(t, 45, aggregates.Max)
+	for _, want := range []int{1, 10, 20, 30, 45} {
+		assert.Equal(t, want, heap.Pop())
+	}
+	assert.Empty(t, heap.index)
+}
+
+func TestUpdateFunc(t *testing.T) {
+	t.Parallel()
+
+	type job struct {
+		id       int
+		priority int
+	}
+	heap := NewHeap[job](3, func(a, b job) bool { return a.priority < b.priority },
+		WithIdentity[job](func(j job) int { return j.id }))
+	for id, priority := range []int{30, 10, 20} {
+		heap.Push(job{id, priority})
+	}
+
+	boost := func(j job) job { j.priority -= 25; return j }
+	assert.True(t, heap.UpdateFunc(job{id: 0}, boost))
+	assert.False(t, heap.UpdateFunc(job{id: 7}, boost))
+	got, _ := heap.Get(job{id: 0})
+	assert.Equal(t, 5, got.priority)
+
+	for _, want := range []int{0, 1, 2} {
+		assert.Equal(t, want, heap.Pop().id)
+	}
+}
+
+func TestUpdateDijkstra(t *testing.T) {
+	t.Parallel()
+
+	// Distances are packed as dist<<8 | node so the heap orders by distance.
+	edges := map[int][][2]int{
+		0: {{1, 4}, {2, 1}},
+		2: {{1, 2}, {3, 5}},
+		1: {{3, 1}},
+	}
+	dist := map[int]int{0: 0}
+	heap := NewHeap[int](4, func(a, b int) bool { return a < b })
+	heap.Push(0)
+	for heap.heapSize > 0 {
+		u := heap.Pop() & 0xff
+		for _, e := range edges[u] {
+			v, alt := e[0], dist[u]+e[1]
+			old, seen := dist[v]
+			switch {
+			case !seen:
+				heap.Push(alt<<8 | v)
+			case alt < old:
+				assert.True(t, heap.Update(old<<8|v, alt<<8|v))
+			default:
+				continue
+			}
+			dist[v] = alt
+		}
+	}
+	assert.Equal(t, map[int]int{0: 0, 1: 3, 2: 1, 3: 4}, dist)
+}
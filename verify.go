@@ -0,0 +1,93 @@
+package heap
+
+import "fmt"
+
+// Verify checks the heap's internal consistency: that no element orders
+// before its parent, that the index records exactly the position of every
+// element, and that handles and lazily removed elements agree with the
+// contents. It returns nil if the heap is consistent and an error wrapping
+// ErrCorrupt that describes the first violation otherwise. It is meant for
+// tests, fuzzing and debugging; it costs O(n) and does not modify the heap.
+func (h *Heap[T]) Verify() error {
+	if h.heapSize < 0 || h.heapSize > len(h.data) {
+		return fmt.Errorf("%w: size %d exceeds storage of %d", ErrCorrupt, h.heapSize, len(h.data))
+	}
+	if !h.dirty {
+		// Staged elements are allowed to break the heap property until merged.
+		for i := 1; i < h.heapSize-h.staged; i++ {
+			if p := h.parent(i); h.lessFunc(h.data[i], h.data[p]) {
+				return fmt.Errorf("%w: element %v at %d orders before its parent %v at %d",
+					ErrCorrupt, h.data[i], i, h.data[p], p)
+			}
+		}
+	}
+
+	data := h.data[:h.heapSize]
+	var err error
+	switch {
+	case h.noIndex:
+	case h.keyed != nil:
+		err = h.keyed.verify(data)
+	case h.hash != nil:
+		err = verifyIndex(h.hashed, len(data), func(i int) uint64 { return h.hash(data[i]) })
+	default:
+		err = verifyIndex(h.index, len(data), func(i int) T { return data[i] })
+	}
+	if err != nil {
+		return err
+	}
+
+	if h.items != nil {
+		for i, it := range h.items[:min(h.heapSize, len(h.items))] {
+			if it != nil && (it.heap != h || it.index != i) {
+				return fmt.Errorf("%w: handle at %d refers to position %d", ErrCorrupt, i, it.index)
+			}
+		}
+	}
+	if h.wait != nil && len(h.wait.enqueued) < h.heapSize {
+		return fmt.Errorf("%w: %d enqueue times for %d elements", ErrCorrupt, len(h.wait.enqueued), h.heapSize)
+	}
+	if h.ttl != nil && len(h.ttl.expires) < h.heapSize {
+		return fmt.Errorf("%w: %d expiry times for %d elements", ErrCorrupt, len(h.ttl.expires), h.heapSize)
+	}
+
+	dead := 0
+	for v, n := range h.tombs {
+		if c := h.count(v); n > c {
+			return fmt.Errorf("%w: %d deletions of %v with %d in the heap", ErrCorrupt, n, v, c)
+		}
+		dead += n
+	}
+	if dead != h.dead {
+		return fmt.Errorf("%w: %d deletions recorded, %d counted", ErrCorrupt, h.dead, dead)
+	}
+	return nil
+}
+
+// verifyIndex checks that index records each of n positions exactly once,
+// under the key of the element stored there.
+func verifyIndex[K comparable](index map[K][]int, n int, key func(i int) K) error {
+	seen := make([]bool, n)
+	total := 0
+	for k, positions := range index {
+		if len(positions) == 0 {
+			return fmt.Errorf("%w: index entry %v has no positions", ErrCorrupt, k)
+		}
+		for _, i := range positions {
+			switch {
+			case i < 0 || i >= n:
+				return fmt.Errorf("%w: index entry %v records position %d outside [0, %d)", ErrCorrupt, k, i, n)
+			case seen[i]:
+				return fmt.Errorf("%w: position %d is indexed twice", ErrCorrupt, i)
+			case key(i) != k:
+				return fmt.Errorf("%w: index entry %v records position %d holding key %v", ErrCorrupt, k, i, key(i))
+			}
+			seen[i] = true
+			total++
+		}
+	}
+	if total != n {
+		return fmt.Errorf("%w: index records %d of %d positions", ErrCorrupt, total, n)
+	}
+	return nil
+}
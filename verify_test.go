@@ -0,0 +1,91 @@
+package heap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapVerify(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	tests := []struct {
+		name    string
+		options []Option[int]
+	}{
+		{name: "default"},
+		{name: "hash index", options: []Option[int]{WithHashIndex[int](func(v int) uint64 { return uint64(v % 7) })}},
+		{name: "identity", options: []Option[int]{WithIdentity[int](func(v int) int { return v / 2 })}},
+		{name: "no index", options: []Option[int]{WithoutIndex[int]()}},
+		{name: "staging", options: []Option[int]{WithStagingBuffer[int](8)}},
+		{name: "lazy", options: []Option[int]{WithLazyHeapify[int]()}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewSource(1))
+			heap := NewHeap[int](3, less, tt.options...)
+			var handles []*Item[int]
+			for i := 0; i < 2000; i++ {
+				v := rng.Intn(500)
+				switch rng.Intn(6) {
+				case 0:
+					heap.Pop()
+				case 1:
+					heap.Remove(v)
+				case 2:
+					heap.Update(v, rng.Intn(500))
+				case 3:
+					heap.LazyRemove(v)
+				case 4:
+					handles = append(handles, heap.PushHandle(v))
+				default:
+					heap.Push(v)
+				}
+				if !assert.NoError(t, heap.Verify(), "after operation %d", i) {
+					return
+				}
+			}
+			for _, it := range handles {
+				heap.RemoveHandle(it)
+			}
+			assert.NoError(t, heap.Verify())
+		})
+	}
+}
+
+func TestHeapVerifyDetectsCorruption(t *testing.T) {
+	t.Parallel()
+
+	build := func() *Heap[int] {
+		heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+		for _, v := range []int{1, 2, 3, 4, 5} {
+			heap.Push(v)
+		}
+		return heap
+	}
+
+	order := build()
+	order.data[0], order.data[4] = order.data[4], order.data[0]
+	assert.ErrorIs(t, order.Verify(), ErrCorrupt)
+
+	index := build()
+	index.index[3] = []int{0}
+	assert.ErrorIs(t, index.Verify(), ErrCorrupt)
+
+	missing := build()
+	delete(missing.index, 5)
+	assert.ErrorIs(t, missing.Verify(), ErrCorrupt)
+
+	tombs := build()
+	tombs.LazyRemove(2)
+	tombs.dead++
+	assert.ErrorIs(t, tombs.Verify(), ErrCorrupt)
+
+	assert.NoError(t, build().Verify())
+}
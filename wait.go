@@ -0,0 +1,156 @@
+package heap
+
+import (
+	"sort"
+	"time"
+)
+
+// waitSampleSize is the number of recent wait times kept for percentiles.
+const waitSampleSize = 1024
+
+// waitTracker records when each element was pushed so the time it spent
+// queued can be reported when it is popped.
+type waitTracker[T comparable] struct {
+	clock    Clock
+	onPop    func(T, time.Duration)
+	enqueued []time.Time // Enqueue time of each element, parallel to Heap.data
+	waits    waitRecorder
+}
+
+// waitRecorder accumulates observed wait times.
+type waitRecorder struct {
+	count   int
+	total   time.Duration
+	max     time.Duration
+	samples []time.Duration // Ring buffer of the most recent wait times
+	next    int
+}
+
+// WaitStats summarizes how long popped elements waited in the heap.
+// Percentiles are computed over the most recent 1024 pops.
+type WaitStats struct {
+	Count int           // Number of elements popped since tracking began
+	Mean  time.Duration // Mean wait across all popped elements
+	Max   time.Duration // Longest wait observed
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+}
+
+// WithWaitTracking is an option that stamps every pushed element with its
+// enqueue time and measures how long it waited when popped. If onPop is not
+// nil it is called with each popped element and its wait. If clock is nil the
+// system clock is used.
+func WithWaitTracking[T comparable](clock Clock, onPop func(T, time.Duration)) Option[T] {
+	return func(h *Heap[T]) {
+		if clock == nil {
+			clock = SystemClock{}
+		}
+		h.wait = &waitTracker[T]{clock: clock, onPop: onPop}
+	}
+}
+
+// WaitStats returns aggregate wait-time statistics for popped elements.
+// It returns the zero value if wait tracking is not enabled.
+func (h *Heap[T]) WaitStats() WaitStats {
+	if h.wait == nil {
+		return WaitStats{}
+	}
+	return h.wait.waits.stats()
+}
+
+// stats summarizes the recorded wait times.
+func (w *waitRecorder) stats() WaitStats {
+	if w.count == 0 {
+		return WaitStats{}
+	}
+	sorted := append([]time.Duration(nil), w.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return WaitStats{
+		Count: w.count,
+		Mean:  w.total / time.Duration(w.count),
+		Max:   w.max,
+		P50:   percentile(sorted, 0.50),
+		P90:   percentile(sorted, 0.90),
+		P99:   percentile(sorted, 0.99),
+	}
+}
+
+// OldestWait returns how long the longest-waiting element has been queued.
+// It returns zero if the heap is empty or wait tracking is not enabled.
+func (h *Heap[T]) OldestWait() time.Duration {
+	if h.wait == nil || h.heapSize == 0 {
+		return 0
+	}
+	oldest := h.wait.enqueued[0]
+	for _, t := range h.wait.enqueued[1:h.heapSize] {
+		if t.Before(oldest) {
+			oldest = t
+		}
+	}
+	return h.wait.clock.Now().Sub(oldest)
+}
+
+// HeadWait returns how long the element Peek would return has been queued.
+// It returns zero if the heap is empty or wait tracking is not enabled.
+func (h *Heap[T]) HeadWait() time.Duration {
+	if h.wait == nil {
+		return 0
+	}
+	h.ensureTop()
+	if h.heapSize == 0 {
+		return 0
+	}
+	return h.wait.clock.Now().Sub(h.wait.enqueued[0])
+}
+
+// stamp records the enqueue time for the element at index i.
+func (w *waitTracker[T]) stamp(i int) {
+	now := w.clock.Now()
+	if i == len(w.enqueued) {
+		w.enqueued = append(w.enqueued, now)
+		return
+	}
+	w.enqueued[i] = now
+}
+
+// observe records the wait of element value, which was stored at index i.
+func (w *waitTracker[T]) observe(value T, i int) {
+	wait := w.clock.Now().Sub(w.enqueued[i])
+	w.waits.record(wait)
+	if w.onPop != nil {
+		w.onPop(value, wait)
+	}
+}
+
+// record adds one observed wait.
+func (w *waitRecorder) record(wait time.Duration) {
+	w.count++
+	w.total += wait
+	if wait > w.max {
+		w.max = wait
+	}
+	if len(w.samples) < waitSampleSize {
+		w.samples = append(w.samples, wait)
+	} else {
+		w.samples[w.next] = wait
+		w.next = (w.next + 1) % waitSampleSize
+	}
+}
+
+// percentile returns the p-th percentile of an ascending slice of durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(p*float64(len(sorted)-1)+0.5)]
+}
+
+// restamp discards the enqueue times and stamps n elements with the current
+// time, for when the elements arrive from a heap that did not track them.
+func (w *waitTracker[T]) restamp(n int) {
+	w.enqueued = w.enqueued[:0]
+	for i := 0; i < n; i++ {
+		w.stamp(i)
+	}
+}
@@ -0,0 +1,66 @@
+package heap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitTracking(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+
+	waits := make(map[int]time.Duration)
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b },
+		WithWaitTracking[int](clock, func(v int, wait time.Duration) { waits[v] = wait }))
+
+	heap.Push(5)
+	clock.RunUntil(start.Add(time.Second))
+	heap.Push(1)
+	heap.Push(3)
+	clock.RunUntil(start.Add(4 * time.Second))
+
+	assert.Equal(t, 4*time.Second, heap.OldestWait())
+
+	assert.Equal(t, 1, heap.Pop())
+	assert.Equal(t, 3, heap.Pop())
+	assert.Equal(t, 5, heap.Pop())
+	assert.Equal(t, map[int]time.Duration{1: 3 * time.Second, 3: 3 * time.Second, 5: 4 * time.Second}, waits)
+	assert.Zero(t, heap.OldestWait())
+
+	stats := heap.WaitStats()
+	assert.Equal(t, 3, stats.Count)
+	assert.Equal(t, 4*time.Second, stats.Max)
+	assert.Equal(t, 3*time.Second, stats.P50)
+	assert.Equal(t, 4*time.Second, stats.P99)
+	assert.Equal(t, 10*time.Second/3, stats.Mean)
+	assert.Equal(t, stats, heap.Stats().Waits, "Stats reports the wait percentiles")
+}
+
+func TestWaitTrackingWithStats(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b },
+		WithStats[int](), WithWaitTracking[int](clock, nil))
+
+	heap.Push(1)
+	heap.Push(2)
+	clock.RunUntil(start.Add(2 * time.Second))
+	heap.Pop()
+
+	stats := heap.Stats()
+	assert.Equal(t, uint64(1), stats.Pops)
+	assert.Equal(t, 1, stats.Waits.Count)
+	assert.Equal(t, 2*time.Second, stats.Waits.P99)
+}
+
+func TestWaitStatsDisabled(t *testing.T) {
+	heap := NewHeap[int](2, func(a, b int) bool { return a < b })
+	heap.Push(1)
+	heap.Pop()
+
+	assert.Equal(t, WaitStats{}, heap.WaitStats())
+	assert.Equal(t, Stats{}, heap.Stats())
+	assert.Zero(t, heap.OldestWait())
+}
@@ -0,0 +1,122 @@
+// Package weakheap provides a generic weak heap, a priority queue designed to
+// minimize element comparisons. Pop performs about log2 n comparisons, half
+// as many as a binary heap, and Push a constant number on average, which
+// matters when the comparator is expensive, for example a call into a CGO
+// scoring function. It takes the same less function as the d-ary heap
+// package and mirrors its Push, Pop and Peek API.
+package weakheap
+
+// Heap is a weak heap ordered by a less function.
+//
+// A weak heap relaxes the heap property: every element only orders no later
+// than the elements in its right subtree, where a per-node reverse bit picks
+// which child counts as right. The root has no left subtree, so it holds the
+// extremal element.
+type Heap[T any] struct {
+	data     []T
+	reverse  []bool // Whether the children of each node are swapped
+	lessFunc func(T, T) bool
+}
+
+// NewHeap creates an empty weak heap ordered by lessFunc.
+func NewHeap[T any](lessFunc func(T, T) bool) *Heap[T] {
+	return &Heap[T]{lessFunc: lessFunc}
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return len(h.data)
+}
+
+// Peek returns the extremal element without removing it. If the heap is
+// empty, it returns the zero value of type T.
+func (h *Heap[T]) Peek() T {
+	if len(h.data) == 0 {
+		var zero T
+		return zero
+	}
+	return h.data[0]
+}
+
+// Push adds a new element to the heap.
+func (h *Heap[T]) Push(value T) {
+	j := len(h.data)
+	h.data = append(h.data, value)
+	h.reverse = append(h.reverse, false)
+	if j&1 == 0 && j > 0 {
+		h.reverse[j/2] = false // Make j a left child so it has no right subtree to check
+	}
+	for j != 0 {
+		i := h.ancestor(j)
+		if h.join(i, j) {
+			break
+		}
+		j = i
+	}
+}
+
+// Pop removes and returns the extremal element. If the heap is empty, it
+// returns the zero value of type T.
+func (h *Heap[T]) Pop() T {
+	var zero T
+	n := len(h.data)
+	if n == 0 {
+		return zero
+	}
+	top := h.data[0]
+	n--
+	h.data[0] = h.data[n]
+	h.data[n] = zero
+	h.data = h.data[:n]
+	h.reverse = h.reverse[:n]
+	if n > 1 {
+		h.siftDown()
+	}
+	return top
+}
+
+// ancestor returns the distinguished ancestor of j: the closest ancestor whose
+// right subtree contains j.
+func (h *Heap[T]) ancestor(j int) int {
+	for (j&1 == 1) == h.reverse[j/2] {
+		j /= 2
+	}
+	return j / 2
+}
+
+// join restores the weak heap order between i and its distinguished
+// descendant j with one comparison. It returns true if they were in order.
+func (h *Heap[T]) join(i, j int) bool {
+	if h.lessFunc(h.data[j], h.data[i]) {
+		h.data[i], h.data[j] = h.data[j], h.data[i]
+		h.reverse[j] = !h.reverse[j]
+		return false
+	}
+	return true
+}
+
+// siftDown restores the order after the root was replaced: it walks down the
+// left spine of the root's right subtree and joins each node on it with the
+// root from the bottom up.
+func (h *Heap[T]) siftDown() {
+	n := len(h.data)
+	k := 1
+	for {
+		left := 2*k + boolToInt(h.reverse[k])
+		if left >= n {
+			break
+		}
+		k = left
+	}
+	for k != 0 {
+		h.join(0, k)
+		k /= 2
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package weakheap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	heap "github.com/ahrav/go-d-ary-heap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeakHeap(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 1, 2, 3, 17, 1000} {
+		rng := rand.New(rand.NewSource(int64(n)))
+		h := NewHeap[int](func(a, b int) bool { return a < b })
+		values := make([]int, n)
+		for i := range values {
+			values[i] = rng.Intn(100)
+			h.Push(values[i])
+		}
+		sort.Ints(values)
+		for _, want := range values {
+			assert.Equal(t, want, h.Peek(), "n=%d", n)
+			assert.Equal(t, want, h.Pop(), "n=%d", n)
+		}
+		assert.Zero(t, h.Len())
+		assert.Zero(t, h.Pop())
+	}
+}
+
+func TestWeakHeapInterleaved(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(7))
+	h := NewHeap[int](func(a, b int) bool { return a > b })
+	reference := heap.NewHeap[int](2, func(a, b int) bool { return a > b })
+	for i := 0; i < 5000; i++ {
+		if rng.Intn(3) == 0 {
+			assert.Equal(t, reference.Pop(), h.Pop())
+			continue
+		}
+		v := rng.Intn(1000)
+		h.Push(v)
+		reference.Push(v)
+	}
+}
+
+func TestWeakHeapComparisons(t *testing.T) {
+	t.Parallel()
+
+	const n = 1 << 12
+	countingLess := func(count *int) func(a, b int) bool {
+		return func(a, b int) bool {
+			*count++
+			return a < b
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	values := rng.Perm(n)
+
+	var weak, binary int
+	w := NewHeap(countingLess(&weak))
+	b := heap.NewHeap(2, countingLess(&binary))
+	for _, v := range values {
+		w.Push(v)
+		b.Push(v)
+	}
+	for i := 0; i < n; i++ {
+		w.Pop()
+		b.Pop()
+	}
+	assert.Less(t, weak, binary*3/4, "weak heap used %d comparisons, binary heap %d", weak, binary)
+}
@@ -0,0 +1,41 @@
+package heap
+
+import "math/rand/v2"
+
+// WithWeightedDequeue is an option that makes Pop choose among priority tiers
+// at random instead of always serving the extremal level, which guarantees
+// lower priorities keep making progress. Tiers are the non-empty levels in
+// priority order: weights[0] is the weight of the first non-empty level,
+// weights[1] of the second, and so on. With weights 80, 15 and 5 the first
+// tier is served 80% of the time. Weights of empty tiers are ignored, and
+// levels beyond the last weight are only served once they move up a tier.
+// If rng is nil a shared random source is used.
+func WithWeightedDequeue[V any, P comparable](weights []float64, rng *rand.Rand) LevelOption[V, P] {
+	return func(q *LevelQueue[V, P]) {
+		q.weights = append([]float64(nil), weights...)
+		q.random = rand.Float64
+		if rng != nil {
+			q.random = rng.Float64
+		}
+	}
+}
+
+// pickTier chooses a tier among the non-empty levels by weight.
+func (q *LevelQueue[V, P]) pickTier() int {
+	tiers := min(len(q.weights), len(q.levels))
+	total := 0.0
+	for _, w := range q.weights[:tiers] {
+		total += w
+	}
+	if total <= 0 {
+		return 0
+	}
+	r := q.random() * total
+	for tier, w := range q.weights[:tiers] {
+		if r < w {
+			return tier
+		}
+		r -= w
+	}
+	return tiers - 1
+}
@@ -0,0 +1,55 @@
+package heap
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedDequeue(t *testing.T) {
+	q := NewLevelQueue[int, int](2, func(a, b int) bool { return a < b },
+		WithWeightedDequeue[int, int]([]float64{80, 15, 5}, rand.New(rand.NewPCG(1, 2))))
+
+	const perLevel = 10000
+	for i := 0; i < perLevel; i++ {
+		for level := 0; level < 3; level++ {
+			q.Push(i, level)
+		}
+	}
+
+	served := make(map[int]int)
+	for i := 0; i < 10000; i++ {
+		_, p := q.Pop()
+		served[p]++
+	}
+	assert.InDelta(t, 8000, served[0], 300)
+	assert.InDelta(t, 1500, served[1], 300)
+	assert.InDelta(t, 500, served[2], 300)
+
+	// Elements within a level still pop in FIFO order.
+	for q.Len() > 0 {
+		v, p := q.Pop()
+		if p == 2 {
+			assert.Equal(t, served[2], v)
+			served[2]++
+		}
+	}
+	assert.Zero(t, q.Levels())
+}
+
+func TestWeightedDequeueSkipsEmptyTiers(t *testing.T) {
+	q := NewLevelQueue[string, int](2, func(a, b int) bool { return a < b },
+		WithWeightedDequeue[string, int]([]float64{0, 1}, nil))
+	q.Push("a", 3)
+	q.Push("b", 7)
+
+	// Only the second tier has weight, so level 7 is served first.
+	v, p := q.Pop()
+	assert.Equal(t, "b", v)
+	assert.Equal(t, 7, p)
+
+	// With one non-empty level left, only the first tier's weight counts.
+	v, _ = q.Pop()
+	assert.Equal(t, "a", v)
+}
@@ -0,0 +1,95 @@
+package heap
+
+import "time"
+
+// WindowedHeap tracks the extremal element of a sliding window over a stream:
+// either the last n values pushed or the values pushed within a time horizon.
+// Values leaving the window are deleted lazily, when they reach the root or
+// when expired values make up most of the heap, so Push and Peek cost
+// O(log n) amortized.
+type WindowedHeap[T comparable] struct {
+	heap    *Heap[windowEntry[T]]
+	seq     uint64          // Sequence number of the next value
+	oldest  uint64          // Sequence number of the oldest value in the window
+	size    int             // Window length in values, zero for a time window
+	horizon time.Duration   // Window length in time
+	clock   Clock           // Time source for a time window
+	times   fifo[time.Time] // Push times of the values in a time window, oldest first
+}
+
+// windowEntry is a value together with its position in the stream.
+type windowEntry[T comparable] struct {
+	value T
+	seq   uint64
+}
+
+// NewWindowedHeap creates a heap with branching factor d whose window is the
+// last size values pushed, ordered by less.
+func NewWindowedHeap[T comparable](d int, less func(T, T) bool, size int) *WindowedHeap[T] {
+	return &WindowedHeap[T]{heap: newWindowHeap(d, less), size: size}
+}
+
+// NewTimeWindowedHeap creates a heap with branching factor d whose window is
+// the values pushed at most horizon ago according to clock, ordered by less.
+// If clock is nil the system clock is used.
+func NewTimeWindowedHeap[T comparable](d int, less func(T, T) bool, horizon time.Duration, clock Clock) *WindowedHeap[T] {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	return &WindowedHeap[T]{heap: newWindowHeap(d, less), horizon: horizon, clock: clock}
+}
+
+// newWindowHeap creates the unindexed heap of entries ordered by value.
+func newWindowHeap[T comparable](d int, less func(T, T) bool) *Heap[windowEntry[T]] {
+	return NewHeap(d, func(a, b windowEntry[T]) bool { return less(a.value, b.value) }, WithoutIndex[windowEntry[T]]())
+}
+
+// Len returns the number of values in the window.
+func (w *WindowedHeap[T]) Len() int {
+	w.advance()
+	return int(w.seq - w.oldest)
+}
+
+// Push adds v to the window, expiring the values it displaces.
+func (w *WindowedHeap[T]) Push(v T) {
+	w.heap.Push(windowEntry[T]{value: v, seq: w.seq})
+	w.seq++
+	if w.clock != nil {
+		w.times.push(w.clock.Now())
+	}
+	w.advance()
+}
+
+// Peek returns the extremal value in the window. If the window is empty, it
+// returns the zero value of type T and false.
+func (w *WindowedHeap[T]) Peek() (T, bool) {
+	w.advance()
+	if w.heap.heapSize == 0 {
+		var zero T
+		return zero, false
+	}
+	return w.heap.Peek().value, true
+}
+
+// advance moves the start of the window to the oldest value still inside it
+// and deletes expired values from the root. Once expired values outnumber the
+// live ones, they are all removed in one O(n) pass.
+func (w *WindowedHeap[T]) advance() {
+	if w.clock != nil {
+		cutoff := w.clock.Now().Add(-w.horizon)
+		for w.times.len() > 0 && w.times.front().Before(cutoff) {
+			w.times.pop()
+			w.oldest++
+		}
+	} else if w.seq > uint64(w.size) {
+		w.oldest = w.seq - uint64(w.size)
+	}
+
+	expired := func(e windowEntry[T]) bool { return e.seq < w.oldest }
+	for w.heap.heapSize > 0 && expired(w.heap.Peek()) {
+		w.heap.Pop()
+	}
+	if live := int(w.seq - w.oldest); w.heap.heapSize > 2*live+16 {
+		w.heap.RemoveIf(expired)
+	}
+}
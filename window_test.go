@@ -0,0 +1,61 @@
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowedHeap(t *testing.T) {
+	t.Parallel()
+
+	const size = 10
+	window := NewWindowedHeap[int](4, func(a, b int) bool { return a > b }, size)
+	_, ok := window.Peek()
+	assert.False(t, ok)
+
+	rng := rand.New(rand.NewSource(5))
+	var stream []int
+	for i := 0; i < 1000; i++ {
+		// A falling trend buries old values below the root.
+		v := 5000 - 5*i + rng.Intn(30)
+		window.Push(v)
+		stream = append(stream, v)
+
+		want := slices.Max(stream[max(0, len(stream)-size):])
+		got, ok := window.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, want, got, "after %d values", len(stream))
+		assert.Equal(t, min(len(stream), size), window.Len())
+	}
+	assert.LessOrEqual(t, window.heap.heapSize, 2*size+16, "expired values were never compacted")
+}
+
+func TestTimeWindowedHeap(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	window := NewTimeWindowedHeap[int](2, func(a, b int) bool { return a < b }, time.Minute, clock)
+
+	window.Push(3)
+	clock.RunUntil(start.Add(30 * time.Second))
+	window.Push(7)
+	window.Push(5)
+	assert.Equal(t, 3, window.Len())
+	v, _ := window.Peek()
+	assert.Equal(t, 3, v)
+
+	clock.RunUntil(start.Add(61 * time.Second))
+	v, _ = window.Peek()
+	assert.Equal(t, 5, v, "3 left the window")
+	assert.Equal(t, 2, window.Len())
+
+	clock.RunUntil(start.Add(2 * time.Minute))
+	_, ok := window.Peek()
+	assert.False(t, ok)
+	assert.Zero(t, window.Len())
+}
@@ -0,0 +1,21 @@
+package heap
+
+// WithoutZeroing is an option that leaves removed elements in the vacated
+// slots of the backing array instead of overwriting them with the zero value.
+// By default the heap clears every slot it vacates, so heaps of pointers or
+// of structs holding pointers do not keep removed objects reachable. Heaps of
+// plain values such as ints have nothing to release and can skip the writes
+// on hot paths.
+func WithoutZeroing[T comparable]() Option[T] {
+	return func(h *Heap[T]) {
+		h.noZero = true
+	}
+}
+
+// vacate clears the slots of the backing array from index i up to j, which
+// no longer hold elements of the heap.
+func (h *Heap[T]) vacate(i, j int) {
+	if !h.noZero {
+		clear(h.data[i:j])
+	}
+}
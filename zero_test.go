@@ -0,0 +1,63 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVacatedSlotsZeroed(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b *int) bool { return *a < *b }
+	tests := []struct {
+		name   string
+		remove func(h *Heap[*int], values []*int)
+	}{
+		{"Pop", func(h *Heap[*int], _ []*int) {
+			for !h.IsEmpty() {
+				h.Pop()
+			}
+		}},
+		{"Remove", func(h *Heap[*int], values []*int) {
+			for _, v := range values {
+				h.Remove(v)
+			}
+		}},
+		{"RemoveIf", func(h *Heap[*int], _ []*int) {
+			h.RemoveIf(func(*int) bool { return true })
+		}},
+		{"Clear", func(h *Heap[*int], _ []*int) {
+			h.Clear()
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, opts := range [][]Option[*int]{nil, {WithoutZeroing[*int]()}} {
+				h := NewHeap(3, less, opts...)
+				values := make([]*int, 20)
+				for i := range values {
+					values[i] = new(int)
+					*values[i] = i
+					h.Push(values[i])
+				}
+				tt.remove(h, values)
+
+				retained := 0
+				for _, v := range h.data {
+					if v != nil {
+						retained++
+					}
+				}
+				if opts == nil {
+					assert.Zero(t, retained, "removed elements still reachable")
+				} else {
+					assert.NotZero(t, retained, "WithoutZeroing cleared the slots")
+				}
+			}
+		})
+	}
+}